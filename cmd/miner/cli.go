@@ -0,0 +1,196 @@
+package main
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// command - подкоманда miner, mode - режим, который она включает в main
+type command struct {
+	name    string
+	mode    string
+	summary string
+	// flags - какие из общих флагов есть у команды, кроме -config
+	flags []string
+}
+
+var commands = []command{
+	{name: "live", mode: "last", summary: "Follow the chain head and save every new block.", flags: []string{"chain"}},
+	{name: "history", mode: "history", summary: "Collect blocks start..end from RPC.", flags: []string{"chain", "start", "end", "start-time", "end-time", "last-blocks", "last", "workers", "batch-size"}},
+	{name: "backfill", mode: "backfill", summary: "Collect only blocks missing from the database in start..end (end 0 - up to the latest stored block).", flags: []string{"chain", "start", "end", "start-time", "end-time", "last-blocks", "last", "workers", "batch-size"}},
+	{name: "verify", mode: "verify", summary: "Audit stored blocks start..end: gaps, duplicates, suspicious values, RPC comparison and optional repair.", flags: []string{"chain", "start", "end", "start-time", "end-time", "last-blocks", "last", "workers", "compare", "sample-rate", "repair", "report"}},
+	{name: "export", mode: "export", summary: "Export stored blocks start..end or a block_time range to csv, jsonl or parquet files.", flags: []string{"chain", "start", "end", "start-time", "end-time", "last", "dir", "format"}},
+	{name: "import", mode: "import", summary: "Load block dumps (export files or raw RPC blocks) into the database.", flags: []string{"chain", "files", "batch-size"}},
+	{name: "reprocess", mode: "reprocess", summary: "Recompute block metrics start..end from the raw block archive.", flags: []string{"chain", "start", "end", "batch-size"}},
+	{name: "mempool", mode: "mempool", summary: "Record pending transaction gas price snapshots.", flags: []string{"chain"}},
+	{name: "migrate", mode: "migrate", summary: "Apply schema migrations for every known chain and exit."},
+	{name: "serve", mode: "serve", summary: "Serve the HTTP and gRPC read APIs without mining.", flags: []string{"chain"}},
+}
+
+// flagValues - значения флагов; в конфиг попадают только явно заданные
+type flagValues struct {
+	config     string
+	chain      string
+	start, end uint64
+	startTime  string
+	endTime    string
+	lastBlocks uint64
+	last       time.Duration
+	workers    int
+	batchSize  int
+	compare    string
+	sampleRate float64
+	repair     bool
+	report     string
+	dir        string
+	format     string
+	files      string
+}
+
+func (v *flagValues) register(fs *flag.FlagSet, names []string) {
+	fs.StringVar(&v.config, "config", configs.DefaultPath(), "path to config.yaml (env MINER_CONFIG)")
+	for _, name := range names {
+		switch name {
+		case "chain":
+			fs.StringVar(&v.chain, name, "", "network name, overrides alchemy.network_name (env MINER_CHAIN)")
+		case "start":
+			fs.Uint64Var(&v.start, name, 0, "first block number, overrides alchemy.start (env MINER_START)")
+		case "end":
+			fs.Uint64Var(&v.end, name, 0, "last block number, overrides alchemy.end (env MINER_END)")
+		case "start-time":
+			fs.StringVar(&v.startTime, name, "", "range start as RFC3339, 2006-01-02 or unix seconds, resolved to a block (env MINER_START_TIME)")
+		case "end-time":
+			fs.StringVar(&v.endTime, name, "", "exclusive range end as RFC3339, 2006-01-02 or unix seconds (env MINER_END_TIME)")
+		case "last-blocks":
+			fs.Uint64Var(&v.lastBlocks, name, 0, "the last N blocks up to the current head (env MINER_LAST_BLOCKS)")
+		case "last":
+			fs.DurationVar(&v.last, name, 0, "blocks of the last duration up to the current head, e.g. 6h (env MINER_LAST)")
+		case "workers":
+			fs.IntVar(&v.workers, name, 0, "parallel RPC workers, overrides alchemy.workers (env MINER_WORKERS)")
+		case "batch-size":
+			fs.IntVar(&v.batchSize, name, 0, "blocks per database batch, overrides alchemy.batch_size (env MINER_BATCH_SIZE)")
+		case "compare":
+			fs.StringVar(&v.compare, name, "", "RPC comparison: none, sample or all, overrides verify.compare")
+		case "sample-rate":
+			fs.Float64Var(&v.sampleRate, name, 0, "share of blocks compared in sample mode, overrides verify.sample_rate")
+		case "repair":
+			fs.BoolVar(&v.repair, name, false, "refetch and overwrite broken blocks, overrides verify.repair")
+		case "report":
+			fs.StringVar(&v.report, name, "", "path of the JSON report, overrides verify.report")
+		case "dir":
+			fs.StringVar(&v.dir, name, "", "output directory, overrides export.dir")
+		case "format":
+			fs.StringVar(&v.format, name, "", "csv, jsonl or parquet, overrides export.format")
+		case "files":
+			fs.StringVar(&v.files, name, "", "comma separated glob patterns, overrides import.files")
+		}
+	}
+}
+
+// apply переносит в конфиг только флаги, заданные в командной строке
+func (v *flagValues) apply(fs *flag.FlagSet, cfg *configs.Config) {
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "chain":
+			cfg.Alchemy.NetworkName = v.chain
+		case "start":
+			cfg.Alchemy.Start = v.start
+		case "end":
+			cfg.Alchemy.End = v.end
+		case "start-time":
+			cfg.Alchemy.StartTime = v.startTime
+		case "end-time":
+			cfg.Alchemy.EndTime = v.endTime
+		case "last-blocks":
+			cfg.Alchemy.LastBlocks = v.lastBlocks
+		case "last":
+			cfg.Alchemy.Last = v.last
+		case "workers":
+			cfg.Alchemy.Workers = v.workers
+		case "batch-size":
+			cfg.Alchemy.BatchSize = v.batchSize
+		case "compare":
+			cfg.Verify.Compare = v.compare
+		case "sample-rate":
+			cfg.Verify.SampleRate = v.sampleRate
+		case "repair":
+			cfg.Verify.Repair = v.repair
+		case "report":
+			cfg.Verify.Report = v.report
+		case "dir":
+			cfg.Export.Dir = v.dir
+		case "format":
+			cfg.Export.Format = v.format
+		case "files":
+			cfg.Import.Files = strings.Split(v.files, ",")
+		}
+	})
+}
+
+// parseCommand разбирает "miner <command> [flags]" и возвращает конфиг с применёнными флагами.
+// Без команды режим берётся из alchemy.mode, как раньше.
+func parseCommand(args []string) *configs.Config {
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelp(args[0])) {
+		fs := flag.NewFlagSet("miner", flag.ExitOnError)
+		var values flagValues
+		values.register(fs, nil)
+		fs.Usage = func() { usage(fs.Output()) }
+		fs.Parse(args)
+		return configs.LoadConfig(values.config)
+	}
+
+	name := args[0]
+	if isHelp(name) || name == "help" {
+		usage(os.Stdout)
+		os.Exit(0)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		fs := flag.NewFlagSet(cmd.name, flag.ExitOnError)
+		var values flagValues
+		values.register(fs, cmd.flags)
+		fs.Usage = func() {
+			out := fs.Output()
+			fmt.Fprintf(out, "Usage: miner %s [flags]\n\n%s\n\nFlags:\n", cmd.name, cmd.summary)
+			fs.PrintDefaults()
+			fmt.Fprintln(out, "\nOther settings are read from the config file; MINER_DB_HOST, MINER_DB_PORT, MINER_DB_NAME,\nMINER_DB_USER, MINER_DB_PASSWORD and MINER_LIMITER override it as well.")
+		}
+		fs.Parse(args[1:])
+		if fs.NArg() > 0 {
+			fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		cfg := configs.LoadConfig(values.config)
+		values.apply(fs, cfg)
+		cfg.Alchemy.Mode = cmd.mode
+		return cfg
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+	return nil
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: miner <command> [flags]\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out, "\nRun \"miner <command> -help\" for command flags. Without a command the mode is taken\nfrom alchemy.mode in the config file (-config or MINER_CONFIG, default config.yaml).")
+}
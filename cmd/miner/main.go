@@ -1,10 +1,24 @@
 package main
 
 import (
+	"blocks_gas_validators/internal/alerts"
+	"blocks_gas_validators/internal/archive"
 	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/export"
+	"blocks_gas_validators/internal/grpcserver"
+	"blocks_gas_validators/internal/health"
+	"blocks_gas_validators/internal/importer"
+	"blocks_gas_validators/internal/migrate"
+	"blocks_gas_validators/internal/miner/alchemy"
 	collect "blocks_gas_validators/internal/miner/alchemy/collector"
 	db "blocks_gas_validators/internal/miner/alchemy/db/postgresql"
 	"blocks_gas_validators/internal/miner/alchemy/worker"
+	"blocks_gas_validators/internal/oracle"
+	"blocks_gas_validators/internal/server"
+	"blocks_gas_validators/internal/sink"
+	"blocks_gas_validators/internal/stream"
+	"blocks_gas_validators/internal/verify"
+	"blocks_gas_validators/pkg/chains"
 	alchemyClient "blocks_gas_validators/pkg/client/alchemy"
 	"blocks_gas_validators/pkg/client/postgresql"
 	"blocks_gas_validators/pkg/logging"
@@ -15,9 +29,20 @@ import (
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
+	// os.Exit только здесь: внутри run должны отработать все defer (sink'и, клиенты)
+	os.Exit(run())
+}
+
+// run запускает выбранный режим и возвращает код выхода процесса
+func run() int {
+	// Флаги разбираются до всего остального, чтобы -help не трогал базу и RPC
+	cfg := parseCommand(os.Args[1:])
+
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		sigCh := make(chan os.Signal, 1)
@@ -29,15 +54,185 @@ func main() {
 	logger := logging.GetLogger()
 	logger.Infof("Logger initialized successfully")
 
-	cfg := configs.GetConfig()
-
 	postgreSQLClient, err := postgresql.NewClient(ctx, 3, cfg.Storage, logger)
 	if err != nil {
 		logger.Fatalf("%v", err)
 	}
 	defer postgreSQLClient.Close()
 
-	repository := db.NewRepository(postgreSQLClient, logger)
+	migrator := migrate.NewRunner(postgreSQLClient, logger)
+	// migrate только готовит схему для всех известных сетей и выходит
+	if cfg.Alchemy.Mode == "migrate" {
+		if err := migrator.Up(ctx, migrate.Chains()...); err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+		logger.Infof("migrations applied")
+		return 0
+	}
+	if cfg.Migrations.Auto {
+		if err := migrator.Up(ctx, cfg.Alchemy.NetworkName); err != nil {
+			logger.Fatalf("failed to apply migrations: %v", err)
+		}
+	}
+
+	partitions, err := db.NewPartitionManager(postgreSQLClient, cfg.Partitions, logger)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	// Выгрузка и API только читают, остальным режимам нужны партиции заранее и retention
+	if cfg.Alchemy.Mode != "export" && cfg.Alchemy.Mode != "serve" {
+		tables := []string{cfg.Alchemy.NetworkName + "_block_metrics"}
+		if cfg.Transactions.Enabled {
+			tables = append(tables, cfg.Alchemy.NetworkName+"_transactions")
+		}
+		go partitions.Run(ctx, tables)
+	}
+
+	repository := db.NewRepository(postgreSQLClient, partitions, cfg.Transactions.Enabled, logger)
+	reader := db.NewReader(postgreSQLClient, logger)
+
+	// Выгрузка работает только с базой, RPC и API ей не нужны
+	if cfg.Alchemy.Mode == "export" {
+		start := time.Now()
+		exporter := export.NewExporter(reader, cfg.Alchemy.NetworkName, cfg.Export.Dir, cfg.Export.Format, cfg.Export.SplitByDay, logger)
+
+		// Время переводится не в номера через RPC, а в фильтр по block_time
+		from, to, byTime, err := collect.TimeRange(cfg.Alchemy, start)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		if byTime {
+			logger.Infof("Miner started mode: %s from: %s, to: %s", cfg.Alchemy.Mode, from, to)
+			err = exporter.ExportTime(ctx, from, to)
+		} else {
+			logger.Infof("Miner started mode: %s start: %d, end: %d", cfg.Alchemy.Mode, cfg.Alchemy.Start, cfg.Alchemy.End)
+			err = exporter.ExportRange(ctx, cfg.Alchemy.Start, cfg.Alchemy.End)
+		}
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		logger.Infof("Miner stopped Elapsed time: %s", time.Since(start))
+		return 0
+	}
+
+	// Импорт пишет дампы через обычный BlockSaver, без RPC и API
+	if cfg.Alchemy.Mode == "import" {
+		start := time.Now()
+		chain := cfg.Alchemy.NetworkName
+
+		files, err := importer.Files(cfg.Import.Files)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		var listeners []alchemy.BlockListener
+		var rollups *worker.RollupUpdater
+		if cfg.Rollups.Enabled {
+			rollups = worker.NewRollupUpdater(repository, chain, nil, logger)
+			listeners = append(listeners, rollups)
+		}
+		if chains.AlchemyChains[chain].SlotGenesis != 0 {
+			listeners = append(listeners, worker.NewMissedSlotDetector(repository, reader, chain, logger))
+		}
+		if cfg.Validators.Enabled {
+			listeners = append(listeners, worker.NewValidatorTracker(repository, chain, logger))
+		}
+		blockSink := newBlockSink(cfg, repository, logger)
+		defer blockSink.Close()
+		saver := worker.NewBlockSaver(blockSink, chain, logger, listeners...)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go saver.HistoryBatch(ctx, importer.NewImporter(chain, cfg.Alchemy.BatchSize, logger).Run(ctx, files), &wg)
+		logger.Infof("Miner started mode: %s files: %d", cfg.Alchemy.Mode, len(files))
+
+		wg.Wait()
+		if rollups != nil {
+			rollups.Flush(context.WithoutCancel(ctx))
+		}
+		logger.Infof("Miner stopped Elapsed time: %s", time.Since(start))
+		return 0
+	}
+
+	// Пересчёт метрик из архива сырых блоков, без RPC и API
+	if cfg.Alchemy.Mode == "reprocess" {
+		start := time.Now()
+		chain := cfg.Alchemy.NetworkName
+
+		rawArchive := archive.NewFileArchive(cfg.Archive.Dir, cfg.Archive.ChunkBlocks, cfg.Archive.MaxOpenChunks, logger)
+		defer rawArchive.Close()
+
+		var listeners []alchemy.BlockListener
+		var rollups *worker.RollupUpdater
+		if cfg.Rollups.Enabled {
+			rollups = worker.NewRollupUpdater(repository, chain, nil, logger)
+			listeners = append(listeners, rollups)
+		}
+
+		logger.Infof("Miner started mode: %s start: %d, end: %d", cfg.Alchemy.Mode, cfg.Alchemy.Start, cfg.Alchemy.End)
+		reprocessor := archive.NewReprocessor(rawArchive, repository, chain, cfg.Alchemy.BatchSize, logger, listeners...)
+		if err := reprocessor.Run(ctx, cfg.Alchemy.Start, cfg.Alchemy.End); err != nil {
+			logger.Errorf("%v", err)
+		}
+		// Перезаписанные блоки уже учтены в агрегатах, поэтому пересчёт целиком
+		if cfg.Validators.Enabled {
+			if err := repository.RebuildValidators(context.WithoutCancel(ctx), chain); err != nil {
+				logger.Errorf("%v", err)
+			}
+		}
+
+		if rollups != nil {
+			rollups.Flush(context.WithoutCancel(ctx))
+		}
+		logger.Infof("Miner stopped Elapsed time: %s", time.Since(start))
+		return 0
+	}
+
+	httpServer := server.NewServer(cfg.Listen, logger)
+	httpServer.RegisterFeeOracle(oracle.NewFeeOracle(reader, cfg.Oracle.SampleBlocks, logger))
+	httpServer.RegisterQueryAPI(reader)
+	httpServer.Handle("GET /metrics", promhttp.Handler())
+
+	hub := stream.NewHub(logger)
+	httpServer.RegisterBlockStream(hub)
+	if cfg.GRPC.Enabled {
+		grpcServer := grpcserver.NewServer(grpcserver.NewService(reader, hub, logger))
+		if cfg.GRPC.Listen.Type == "" {
+			httpServer.MountGRPC(grpcServer)
+		} else {
+			go func() {
+				if err := grpcserver.Serve(ctx, grpcServer, cfg.GRPC.Listen, logger); err != nil {
+					logger.Errorf("%v", err)
+				}
+			}()
+		}
+	}
+
+	// Только API чтения поверх базы, без RPC и майнинга
+	if cfg.Alchemy.Mode == "serve" {
+		// Без head'ов и вставок: готовность определяет только база
+		chainInfo := chains.AlchemyChains[cfg.Alchemy.NetworkName]
+		httpServer.RegisterHealth(health.NewChecker(
+			cfg.Alchemy.NetworkName,
+			time.Duration(chainInfo.BlockTime*float64(time.Second)),
+			false,
+			false,
+			cfg.Health,
+			map[string]health.Probe{"postgres": postgreSQLClient.Ping},
+		))
+
+		go func() {
+			if err := httpServer.Run(ctx); err != nil {
+				logger.Errorf("%v", err)
+			}
+		}()
+		logger.Infof("Miner started mode: %s", cfg.Alchemy.Mode)
+
+		<-ctx.Done()
+		logger.Infof("Miner stopped")
+		return 0
+	}
 
 	alchemyClient, err := alchemyClient.NewAlchemyClient(cfg.Alchemy, logger)
 	if err != nil {
@@ -45,9 +240,92 @@ func main() {
 	}
 	defer alchemyClient.Close()
 
-	collector := collect.NewBlockCollector(alchemyClient, logger, cfg.Alchemy.Limiter)
+	chainInfo := chains.AlchemyChains[alchemyClient.NetworkName]
+	checker := health.NewChecker(
+		alchemyClient.NetworkName,
+		time.Duration(chainInfo.BlockTime*float64(time.Second)),
+		expectsHeads(cfg.Alchemy.Mode),
+		expectsInserts(cfg.Alchemy.Mode),
+		cfg.Health,
+		map[string]health.Probe{
+			"postgres": postgreSQLClient.Ping,
+			"rpc": func(ctx context.Context) error {
+				_, err := alchemyClient.Client.BlockNumber(ctx)
+				return err
+			},
+		},
+	)
+	httpServer.RegisterHealth(checker)
+
+	go func() {
+		if err := httpServer.Run(ctx); err != nil {
+			logger.Errorf("%v", err)
+		}
+	}()
+
+	headListeners := []alchemy.HeadListener{checker}
+	listeners := []alchemy.BlockListener{hub, checker}
+
+	if cfg.Alerts.Enabled {
+		notifiers := []alerts.Notifier{alerts.NewLogNotifier(logger)}
+		for _, webhook := range cfg.Alerts.Webhooks {
+			notifiers = append(notifiers, alerts.NewWebhookNotifier(webhook))
+		}
+
+		alertEngine, err := alerts.NewEngine(
+			alchemyClient.NetworkName,
+			time.Duration(chainInfo.BlockTime*float64(time.Second)),
+			expectsHeads(cfg.Alchemy.Mode),
+			expectsInserts(cfg.Alchemy.Mode),
+			cfg.Alerts,
+			notifiers,
+			logger,
+		)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		headListeners = append(headListeners, alertEngine)
+		listeners = append(listeners, alertEngine)
+		go alertEngine.Run(ctx)
+	}
+
+	var rawArchive alchemy.Archive
+	if cfg.Archive.Enabled {
+		rawArchive = archive.NewFileArchive(cfg.Archive.Dir, cfg.Archive.ChunkBlocks, cfg.Archive.MaxOpenChunks, logger)
+		defer rawArchive.Close()
+	}
+
+	collector := collect.NewBlockCollector(alchemyClient, rawArchive, logger, cfg.Alchemy.Limiter, headListeners...)
+
+	// Диапазон, заданный временем или относительно head, переводим в номера блоков
+	if cfg.Alchemy.Mode == "history" || cfg.Alchemy.Mode == "backfill" || cfg.Alchemy.Mode == "verify" {
+		resolver := collect.NewBlockResolver(alchemyClient, cfg.Alchemy.Limiter, cfg.Alchemy.BlockTimeCache, logger)
+		if err := resolver.ResolveRange(ctx, &cfg.Alchemy); err != nil {
+			logger.Fatalf("%v", err)
+		}
+	}
+
+	var rollups *worker.RollupUpdater
+	if cfg.Rollups.Enabled {
+		rollups = worker.NewRollupUpdater(repository, alchemyClient.NetworkName, map[string]time.Duration{
+			"1m": cfg.Rollups.MinuteInterval,
+			"1h": cfg.Rollups.HourInterval,
+			"1d": cfg.Rollups.DayInterval,
+		}, logger)
+		listeners = append(listeners, rollups)
+		go rollups.Run(ctx)
+	}
+
+	if chainInfo.SlotGenesis != 0 {
+		listeners = append(listeners, worker.NewMissedSlotDetector(repository, reader, alchemyClient.NetworkName, logger))
+	}
+	if cfg.Validators.Enabled {
+		listeners = append(listeners, worker.NewValidatorTracker(repository, alchemyClient.NetworkName, logger))
+	}
 
-	saver := worker.NewBlockSaver(repository, alchemyClient.NetworkName, logger)
+	blockSink := newBlockSink(cfg, repository, logger)
+	defer blockSink.Close()
+	saver := worker.NewBlockSaver(blockSink, alchemyClient.NetworkName, logger, listeners...)
 
 	if cfg.Alchemy.Mode == "last" {
 		blockChan, err := collector.SubscribeNewBlocks(ctx, cfg.Alchemy.MaxRetries)
@@ -72,11 +350,138 @@ func main() {
 		logger.Infof("Miner started mode: %s start: %d, end: %d", cfg.Alchemy.Mode, cfg.Alchemy.Start, cfg.Alchemy.End)
 
 		wg.Wait()
+		if rollups != nil {
+			// Бэкфилл мог закончиться между тиками, досчитываем затронутые бакеты
+			rollups.Flush(context.WithoutCancel(ctx))
+		}
 		end := time.Now()
 		elapsed := end.Sub(start)
 		logger.Infof("Miner stopped Elapsed time: %s", elapsed)
 
+	} else if cfg.Alchemy.Mode == "backfill" {
+		start := time.Now()
+		end := cfg.Alchemy.End
+		if end == 0 {
+			latest, err := reader.LatestBlock(ctx, alchemyClient.NetworkName)
+			if err != nil {
+				logger.Fatalf("find latest stored block: %v", err)
+			}
+			end = latest.BlockNumber
+		}
+
+		gaps, err := reader.MissingRanges(ctx, alchemyClient.NetworkName, cfg.Alchemy.Start, end)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		logger.Infof("Miner started mode: %s start: %d, end: %d, gaps: %d", cfg.Alchemy.Mode, cfg.Alchemy.Start, end, len(gaps))
+
+		// Каждый пропуск собирается как обычный history прогон
+		for _, gap := range gaps {
+			if ctx.Err() != nil {
+				break
+			}
+			logger.Infof("backfilling blocks %d-%d", gap.From, gap.To)
+
+			gapCfg := cfg.Alchemy
+			gapCfg.Start, gapCfg.End = gap.From, gap.To
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go saver.HistoryBatch(ctx, collector.CollectHistoryBlocksBatch(ctx, gapCfg), &wg)
+			wg.Wait()
+		}
+
+		if rollups != nil {
+			rollups.Flush(context.WithoutCancel(ctx))
+		}
+		logger.Infof("Miner stopped Elapsed time: %s", time.Since(start))
+
+	} else if cfg.Alchemy.Mode == "verify" {
+		start := time.Now()
+		verifier, err := verify.NewVerifier(reader, repository, saver, collector, alchemyClient.NetworkName, cfg.Verify, cfg.Alchemy.Workers, cfg.Alchemy.Limiter, logger)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+
+		logger.Infof("Miner started mode: %s start: %d, end: %d, compare: %s, repair: %t", cfg.Alchemy.Mode, cfg.Alchemy.Start, cfg.Alchemy.End, cfg.Verify.Compare, cfg.Verify.Repair)
+		report, err := verifier.Run(ctx, cfg.Alchemy.Start, cfg.Alchemy.End)
+		if err != nil {
+			logger.Errorf("%v", err)
+		}
+		// Repair перезаписывает уже учтённые блоки и удаляет дубликаты, агрегаты пересчитываются целиком
+		if cfg.Validators.Enabled && report != nil && report.Repaired > 0 {
+			if err := repository.RebuildValidators(context.WithoutCancel(ctx), alchemyClient.NetworkName); err != nil {
+				logger.Errorf("%v", err)
+			}
+		}
+		if rollups != nil {
+			rollups.Flush(context.WithoutCancel(ctx))
+		}
+		logger.Infof("Miner stopped Elapsed time: %s", time.Since(start))
+		// Ненулевой код, чтобы проверку можно было использовать в cron и CI
+		if err != nil || len(report.Failed) > 0 || (!report.OK() && !cfg.Verify.Repair) {
+			return 1
+		}
+
+	} else if cfg.Alchemy.Mode == "mempool" {
+		txChan, err := collector.SubscribePendingTransactions(ctx)
+		if err != nil {
+			log.Fatalf("subscribe failed: %v", err)
+		}
+
+		includedChan, err := collector.SubscribeIncludedTransactions(ctx)
+		if err != nil {
+			log.Fatalf("subscribe failed: %v", err)
+		}
+
+		mempoolSaver := worker.NewMempoolSaver(repository, alchemyClient.NetworkName, cfg.Mempool.Window, cfg.Mempool.SnapshotInterval, logger)
+		go mempoolSaver.Run(ctx, txChan, includedChan)
+		logger.Infof("Miner started mode: %s window: %s, snapshot interval: %s", cfg.Alchemy.Mode, cfg.Mempool.Window, cfg.Mempool.SnapshotInterval)
+
+		<-ctx.Done()
+		logger.Infof("Miner stopped")
+
 	} else {
 		logger.Fatalf("Invalid mode: %s", cfg.Alchemy.Mode)
 	}
+	return 0
+}
+
+// expectsHeads - режимы, которые подписаны на новые head'ы
+func expectsHeads(mode string) bool {
+	return mode == "last"
+}
+
+// expectsInserts - режимы, которые постоянно сохраняют блоки.
+// history и backfill пишут без head'ов, verify пишет только при repair, mempool не пишет блоки
+func expectsInserts(mode string) bool {
+	return mode == "last" || mode == "history" || mode == "backfill"
+}
+
+// newBlockSink собирает Postgres и включённые в конфиге дополнительные sink'и
+func newBlockSink(cfg *configs.Config, repository alchemy.Storage, logger *logging.Logger) alchemy.Sink {
+	var secondary []alchemy.Sink
+	buffered := func(s alchemy.Sink) alchemy.Sink {
+		return sink.NewBuffered(s, cfg.Sinks.Buffer, cfg.Sinks.Retries, cfg.Sinks.RetryDelay, logger)
+	}
+
+	if cfg.Sinks.Stdout {
+		secondary = append(secondary, buffered(sink.NewStdoutSink()))
+	}
+	if cfg.Sinks.JSONL.Enabled {
+		jsonlSink, err := sink.NewJSONLSink(cfg.Sinks.JSONL.Dir)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		secondary = append(secondary, buffered(jsonlSink))
+	}
+	if cfg.Sinks.NATS.Enabled {
+		natsSink, err := sink.NewNATSSink(cfg.Sinks.NATS.URL, cfg.Sinks.NATS.Subject)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		secondary = append(secondary, buffered(natsSink))
+	}
+
+	return sink.NewFanout(sink.NewPostgresSink(repository), logger, secondary...)
 }
@@ -0,0 +1,282 @@
+package alerts
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+
+	ruleTypeBlock    = "block"
+	ruleTypeNoHead   = "no_head"
+	ruleTypeNoInsert = "no_insert"
+)
+
+type Alert struct {
+	Rule        string    `json:"rule"`
+	Chain       string    `json:"chain"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message"`
+	Value       float64   `json:"value"`
+	BlockNumber uint64    `json:"block_number,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+type ruleState struct {
+	cfg configs.AlertRuleConfig
+	// streak - сколько блоков подряд выполняется условие
+	streak int
+	firing bool
+	// notified - по текущему эпизоду firing ушло уведомление, только тогда нужно resolved
+	notified   bool
+	lastFiring time.Time
+}
+
+// Engine проверяет правила на каждом сохранённом блоке и по таймеру.
+// Повторные срабатывания подавляются, пока правило не разрешится
+// и не пройдёт cooldown.
+type Engine struct {
+	chain     string
+	blockTime time.Duration
+	cfg       configs.AlertsConfig
+	notifiers []Notifier
+	logger    *logging.Logger
+	// no_head правила проверяются только там, где приходят head'ы (live),
+	// no_insert - только в режимах, которые сохраняют блоки
+	expectHeads   bool
+	expectInserts bool
+
+	mu         sync.Mutex
+	rules      []*ruleState
+	startedAt  time.Time
+	lastHead   time.Time
+	lastInsert time.Time
+
+	queue chan Alert
+}
+
+func NewEngine(chain string, blockTime time.Duration, expectHeads, expectInserts bool, cfg configs.AlertsConfig, notifiers []Notifier, logger *logging.Logger) (*Engine, error) {
+	e := &Engine{
+		chain:         chain,
+		blockTime:     blockTime,
+		cfg:           cfg,
+		notifiers:     notifiers,
+		logger:        logger,
+		expectHeads:   expectHeads,
+		expectInserts: expectInserts,
+		startedAt:     time.Now(),
+		queue:         make(chan Alert, 100),
+	}
+
+	for _, rule := range cfg.Rules {
+		if rule.Type == "" {
+			rule.Type = ruleTypeBlock
+		}
+		if err := validateRule(rule); err != nil {
+			return nil, fmt.Errorf("alert rule %q: %w", rule.Name, err)
+		}
+		e.rules = append(e.rules, &ruleState{cfg: rule})
+	}
+
+	return e, nil
+}
+
+func validateRule(rule configs.AlertRuleConfig) error {
+	switch rule.Type {
+	case ruleTypeBlock:
+		if _, ok := blockMetrics[rule.Metric]; !ok {
+			return fmt.Errorf("unknown metric %q", rule.Metric)
+		}
+		if _, ok := operators[rule.Op]; !ok {
+			return fmt.Errorf("unknown operator %q", rule.Op)
+		}
+		if rule.ForBlocks <= 0 {
+			return fmt.Errorf("for_blocks must be positive")
+		}
+	case ruleTypeNoHead, ruleTypeNoInsert:
+		if rule.ForBlockTimes <= 0 {
+			return fmt.Errorf("for_block_times must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+	return nil
+}
+
+var blockMetrics = map[string]func(b *alchemy.Block) float64{
+	"gas_avg":            func(b *alchemy.Block) float64 { return b.GasStats.Avg },
+	"gas_min":            func(b *alchemy.Block) float64 { return b.GasStats.Min },
+	"gas_max":            func(b *alchemy.Block) float64 { return b.GasStats.Max },
+	"gas_stddev":         func(b *alchemy.Block) float64 { return b.GasStats.Stddev },
+	"block_fullness":     func(b *alchemy.Block) float64 { return b.BlockFullness },
+	"transactions_count": func(b *alchemy.Block) float64 { return float64(b.TransactionsCount) },
+	"gas_used":           func(b *alchemy.Block) float64 { return float64(b.GasUsed) },
+	"base_fee":           func(b *alchemy.Block) float64 { return b.BaseFee },
+	"unknown_tx_count":   func(b *alchemy.Block) float64 { return float64(b.UnknownTxCount) },
+}
+
+var operators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+func (e *Engine) OnHead(_ uint64) {
+	e.mu.Lock()
+	e.lastHead = time.Now()
+	e.mu.Unlock()
+}
+
+func (e *Engine) OnBlocksSaved(_ context.Context, chain string, blocks []*alchemy.Block) {
+	if chain != e.chain || len(blocks) == 0 {
+		return
+	}
+
+	sorted := make([]*alchemy.Block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BlockNumber < sorted[j].BlockNumber })
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	e.lastInsert = now
+
+	for _, rule := range e.rules {
+		if rule.cfg.Type != ruleTypeBlock {
+			continue
+		}
+		metric := blockMetrics[rule.cfg.Metric]
+		op := operators[rule.cfg.Op]
+
+		for _, block := range sorted {
+			value := metric(block)
+			if !op(value, rule.cfg.Threshold) {
+				rule.streak = 0
+				if rule.firing {
+					e.resolve(rule, now, fmt.Sprintf("%s = %.4f at block %d", rule.cfg.Metric, value, block.BlockNumber), value, block.BlockNumber)
+				}
+				continue
+			}
+
+			rule.streak++
+			if rule.streak >= rule.cfg.ForBlocks {
+				msg := fmt.Sprintf("%s %s %.4f for %d blocks, now %.4f at block %d",
+					rule.cfg.Metric, rule.cfg.Op, rule.cfg.Threshold, rule.streak, value, block.BlockNumber)
+				e.fire(rule, now, msg, value, block.BlockNumber)
+			}
+		}
+	}
+}
+
+// Run проверяет временные правила и рассылает уведомления до отмены контекста
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	go e.dispatch(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Infof("alert engine stopped for chain: %s", e.chain)
+			return
+		case now := <-ticker.C:
+			e.checkTimers(now)
+		}
+	}
+}
+
+func (e *Engine) checkTimers(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, rule := range e.rules {
+		var last time.Time
+		switch {
+		case rule.cfg.Type == ruleTypeNoHead && e.expectHeads:
+			last = e.lastHead
+		case rule.cfg.Type == ruleTypeNoInsert && e.expectInserts:
+			last = e.lastInsert
+		default:
+			continue
+		}
+		// Событий ещё не было - считаем от старта
+		if last.IsZero() {
+			last = e.startedAt
+		}
+
+		silence := now.Sub(last)
+		limit := time.Duration(rule.cfg.ForBlockTimes * float64(e.blockTime))
+		if silence > limit {
+			e.fire(rule, now, fmt.Sprintf("no %s for %s (limit %s)", eventName(rule.cfg.Type), silence.Round(time.Second), limit), silence.Seconds(), 0)
+		} else if rule.firing {
+			e.resolve(rule, now, fmt.Sprintf("%s received again", eventName(rule.cfg.Type)), silence.Seconds(), 0)
+		}
+	}
+}
+
+func eventName(ruleType string) string {
+	if ruleType == ruleTypeNoHead {
+		return "new head"
+	}
+	return "inserted block"
+}
+
+func (e *Engine) fire(rule *ruleState, now time.Time, msg string, value float64, block uint64) {
+	// Пока правило горит, повторяем уведомление не чаще раза в cooldown.
+	// Cooldown действует и между эпизодами, так что мигающее условие не спамит
+	rule.firing = true
+	if !rule.lastFiring.IsZero() && now.Sub(rule.lastFiring) < e.cfg.Cooldown {
+		return
+	}
+	rule.notified = true
+	rule.lastFiring = now
+	e.enqueue(Alert{Rule: rule.cfg.Name, Chain: e.chain, Status: StatusFiring, Message: msg, Value: value, BlockNumber: block, At: now})
+}
+
+func (e *Engine) resolve(rule *ruleState, now time.Time, msg string, value float64, block uint64) {
+	notified := rule.notified
+	rule.firing = false
+	rule.notified = false
+	// О подавленном эпизоде никто не знал, resolved для него было бы лишним
+	if !notified {
+		return
+	}
+	e.enqueue(Alert{Rule: rule.cfg.Name, Chain: e.chain, Status: StatusResolved, Message: msg, Value: value, BlockNumber: block, At: now})
+}
+
+// enqueue не блокирует путь сохранения блоков: при переполнении уведомление теряется
+func (e *Engine) enqueue(alert Alert) {
+	select {
+	case e.queue <- alert:
+	default:
+		e.logger.Errorf("alert queue full, dropping %s alert %s", alert.Status, alert.Rule)
+	}
+}
+
+func (e *Engine) dispatch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-e.queue:
+			for _, n := range e.notifiers {
+				if err := n.Notify(ctx, alert); err != nil {
+					e.logger.Errorf("failed to deliver alert %s: %v", alert.Rule, err)
+				}
+			}
+		}
+	}
+}
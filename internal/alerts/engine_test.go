@@ -0,0 +1,172 @@
+package alerts
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logging.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return &logging.Logger{Entry: logrus.NewEntry(l)}
+}
+
+// webhookStandIn - локальный приёмник вебхука, отдаёт полученные алерты в канал
+func webhookStandIn(t *testing.T) (*httptest.Server, <-chan Alert) {
+	t.Helper()
+	received := make(chan Alert, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- alert
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func startEngine(t *testing.T, cfg configs.AlertsConfig, expectHeads, expectInserts bool) (*Engine, <-chan Alert) {
+	t.Helper()
+	srv, received := webhookStandIn(t)
+	cfg.CheckInterval = time.Hour
+
+	e, err := NewEngine("ethereum", 12*time.Second, expectHeads, expectInserts, cfg,
+		[]Notifier{NewWebhookNotifier(configs.WebhookConfig{URL: srv.URL})}, testLogger())
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go e.Run(ctx)
+	return e, received
+}
+
+func next(t *testing.T, received <-chan Alert) Alert {
+	t.Helper()
+	select {
+	case alert := <-received:
+		return alert
+	case <-time.After(5 * time.Second):
+		t.Fatal("no alert delivered")
+		return Alert{}
+	}
+}
+
+func expect(t *testing.T, alert Alert, rule, status string) {
+	t.Helper()
+	if alert.Rule != rule || alert.Status != status {
+		t.Fatalf("got %s %s, want %s %s", alert.Rule, alert.Status, rule, status)
+	}
+}
+
+func saveBlock(e *Engine, number uint64, gasAvg float64, txCount int) {
+	e.OnBlocksSaved(context.Background(), "ethereum", []*alchemy.Block{{
+		BlockNumber:       number,
+		TransactionsCount: txCount,
+		GasStats:          alchemy.GasStats{Avg: gasAvg},
+	}})
+}
+
+var (
+	highGas = configs.AlertRuleConfig{Name: "high_gas", Metric: "gas_avg", Op: ">", Threshold: 100, ForBlocks: 2}
+	busy    = configs.AlertRuleConfig{Name: "busy", Metric: "transactions_count", Op: ">", Threshold: 1000, ForBlocks: 1}
+)
+
+func TestWebhookFiringAndResolve(t *testing.T) {
+	e, received := startEngine(t, configs.AlertsConfig{Rules: []configs.AlertRuleConfig{highGas}}, true, true)
+
+	saveBlock(e, 1, 150, 10)
+	saveBlock(e, 2, 150, 10)
+	saveBlock(e, 3, 50, 10)
+
+	firing := next(t, received)
+	expect(t, firing, "high_gas", StatusFiring)
+	if firing.BlockNumber != 2 || firing.Value != 150 || firing.Chain != "ethereum" {
+		t.Fatalf("unexpected firing alert: %+v", firing)
+	}
+
+	resolved := next(t, received)
+	expect(t, resolved, "high_gas", StatusResolved)
+	if resolved.BlockNumber != 3 {
+		t.Fatalf("resolved at block %d, want 3", resolved.BlockNumber)
+	}
+}
+
+func TestCooldownSuppressesFlapping(t *testing.T) {
+	rule := highGas
+	rule.ForBlocks = 1
+	e, received := startEngine(t, configs.AlertsConfig{
+		Cooldown: time.Hour,
+		Rules:    []configs.AlertRuleConfig{rule, busy},
+	}, true, true)
+
+	// Первый эпизод уведомляется целиком, второй попадает в cooldown:
+	// ни firing, ни resolved по нему уйти не должны
+	saveBlock(e, 1, 150, 10)
+	saveBlock(e, 2, 50, 10)
+	saveBlock(e, 3, 150, 10)
+	saveBlock(e, 4, 50, 10)
+	// Метка конца последовательности: очередь упорядочена, следующим должен прийти busy
+	saveBlock(e, 5, 50, 2000)
+
+	expect(t, next(t, received), "high_gas", StatusFiring)
+	expect(t, next(t, received), "high_gas", StatusResolved)
+	expect(t, next(t, received), "busy", StatusFiring)
+}
+
+func TestTimerRulesFollowMode(t *testing.T) {
+	rules := []configs.AlertRuleConfig{
+		{Name: "no_head", Type: ruleTypeNoHead, ForBlockTimes: 5},
+		{Name: "no_insert", Type: ruleTypeNoInsert, ForBlockTimes: 5},
+	}
+	later := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name                       string
+		expectHeads, expectInserts bool
+		want                       []string
+	}{
+		{name: "live", expectHeads: true, expectInserts: true, want: []string{"no_head", "no_insert"}},
+		{name: "history", expectInserts: true, want: []string{"no_insert"}},
+		{name: "verify"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := NewEngine("ethereum", 12*time.Second, tc.expectHeads, tc.expectInserts,
+				configs.AlertsConfig{Rules: rules}, nil, testLogger())
+			if err != nil {
+				t.Fatalf("new engine: %v", err)
+			}
+
+			e.checkTimers(later)
+
+			var got []string
+			for len(e.queue) > 0 {
+				got = append(got, (<-e.queue).Rule)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("fired %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("fired %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package alerts
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/pkg/logging"
+	"blocks_gas_validators/pkg/utilits"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultWebhookTimeout = 5 * time.Second
+	webhookAttempts       = 3
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+type logNotifier struct {
+	logger *logging.Logger
+}
+
+func NewLogNotifier(logger *logging.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) Notify(_ context.Context, alert Alert) error {
+	if alert.Status == StatusResolved {
+		n.logger.Infof("ALERT RESOLVED [%s] %s: %s", alert.Chain, alert.Rule, alert.Message)
+		return nil
+	}
+	n.logger.Warnf("ALERT FIRING [%s] %s: %s", alert.Chain, alert.Rule, alert.Message)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(cfg configs.WebhookConfig) Notifier {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &webhookNotifier{
+		url:    cfg.URL,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	return utilits.DoWithTries(func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("post webhook %s: %w", n.url, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook %s returned %s", n.url, resp.Status)
+		}
+		return nil
+	}, webhookAttempts, time.Second)
+}
@@ -0,0 +1,239 @@
+package archive
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	chunkExt = ".jsonl.gz"
+	// maxRecordSize - сырой блок с транзакциями и квитанциями бывает в десятки мегабайт
+	maxRecordSize = 256 << 20
+)
+
+// fileArchive хранит сырые блоки в <dir>/<chain>/<start>-<end>.jsonl.gz, чанками по chunkBlocks.
+// Блоки приходят от воркеров не по порядку, поэтому держим несколько чанков открытыми.
+// Каждое открытие дописывает в файл новый gzip member, gzip.Reader читает их подряд.
+type fileArchive struct {
+	dir         string
+	chunkBlocks uint64
+	maxOpen     int
+	logger      *logging.Logger
+
+	mu    sync.Mutex
+	open  map[string]*chunkWriter
+	order []string
+}
+
+type chunkWriter struct {
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+func NewFileArchive(dir string, chunkBlocks uint64, maxOpen int, logger *logging.Logger) alchemy.Archive {
+	if chunkBlocks == 0 {
+		chunkBlocks = 10000
+	}
+	if maxOpen <= 0 {
+		maxOpen = 1
+	}
+
+	return &fileArchive{
+		dir:         dir,
+		chunkBlocks: chunkBlocks,
+		maxOpen:     maxOpen,
+		logger:      logger,
+		open:        make(map[string]*chunkWriter),
+	}
+}
+
+func (a *fileArchive) chunkPath(chain string, number uint64) string {
+	start := number / a.chunkBlocks * a.chunkBlocks
+	return filepath.Join(a.dir, chain, fmt.Sprintf("%012d-%012d%s", start, start+a.chunkBlocks-1, chunkExt))
+}
+
+func (a *fileArchive) Put(chain string, raw *alchemy.RawBlock) error {
+	path := a.chunkPath(chain, raw.Number)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w, err := a.writer(path)
+	if err != nil {
+		return err
+	}
+
+	if err := w.enc.Encode(raw); err != nil {
+		return fmt.Errorf("archive block %d: %w", raw.Number, err)
+	}
+	// Flush после каждого блока: при падении процесса теряется не больше последней записи
+	if err := w.gz.Flush(); err != nil {
+		return fmt.Errorf("flush archive %s: %w", path, err)
+	}
+	return nil
+}
+
+func (a *fileArchive) writer(path string) (*chunkWriter, error) {
+	if w, ok := a.open[path]; ok {
+		return w, nil
+	}
+
+	// Закрываем самый давно открытый чанк, чтобы не копить дескрипторы
+	if len(a.order) >= a.maxOpen {
+		oldest := a.order[0]
+		a.order = a.order[1:]
+		if err := a.open[oldest].close(); err != nil {
+			a.logger.Warnf("failed to close archive chunk %s: %v", oldest, err)
+		}
+		delete(a.open, oldest)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create archive dir: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open archive chunk: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	w := &chunkWriter{file: file, gz: gz, enc: json.NewEncoder(gz)}
+	a.open[path] = w
+	a.order = append(a.order, path)
+	return w, nil
+}
+
+func (w *chunkWriter) close() error {
+	err := w.gz.Close()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (a *fileArchive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var errs []error
+	for path, w := range a.open {
+		if err := w.close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", path, err))
+		}
+	}
+	a.open = make(map[string]*chunkWriter)
+	a.order = nil
+	return errors.Join(errs...)
+}
+
+func (a *fileArchive) Scan(ctx context.Context, chain string, from, to uint64, fn func(*alchemy.RawBlock) error) error {
+	chunks, err := a.chunks(chain, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range chunks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := a.scanChunk(path, from, to, fn); err != nil {
+			return fmt.Errorf("scan %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// chunks возвращает файлы чанков, пересекающихся с [from, to], по возрастанию номеров
+func (a *fileArchive) chunks(chain string, from, to uint64) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(a.dir, chain))
+	if err != nil {
+		return nil, fmt.Errorf("read archive dir: %w", err)
+	}
+
+	type chunk struct {
+		path  string
+		start uint64
+	}
+	var chunks []chunk
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, chunkExt) {
+			continue
+		}
+
+		// Границы берём из имени: размер чанка мог меняться между запусками
+		bounds := strings.SplitN(strings.TrimSuffix(name, chunkExt), "-", 2)
+		if len(bounds) != 2 {
+			continue
+		}
+		start, err1 := strconv.ParseUint(bounds[0], 10, 64)
+		end, err2 := strconv.ParseUint(bounds[1], 10, 64)
+		if err1 != nil || err2 != nil || end < from || start > to {
+			continue
+		}
+		chunks = append(chunks, chunk{path: filepath.Join(a.dir, chain, name), start: start})
+	}
+
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i].start < chunks[j].start })
+
+	paths := make([]string, len(chunks))
+	for i, c := range chunks {
+		paths[i] = c.path
+	}
+	return paths, nil
+}
+
+func (a *fileArchive) scanChunk(path string, from, to uint64, fn func(*alchemy.RawBlock) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 1<<20), maxRecordSize)
+
+	for scanner.Scan() {
+		var raw alchemy.RawBlock
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+			a.logger.Warnf("%s: skip broken record: %v", path, err)
+			continue
+		}
+		if raw.Number < from || raw.Number > to {
+			continue
+		}
+		if err := fn(&raw); err != nil {
+			return err
+		}
+	}
+
+	// Незакрытый после падения gzip member обрывается, всё до последнего Flush читается
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			a.logger.Warnf("%s: truncated chunk, read up to the last complete record", path)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	collect "blocks_gas_validators/internal/miner/alchemy/collector"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"fmt"
+)
+
+// Reprocessor пересчитывает метрики блоков из архива и перезаписывает их в базе.
+// RPC не используется, поэтому пересчёт миллионов блоков не тратит compute units.
+type Reprocessor struct {
+	Archive   alchemy.Archive
+	DB        alchemy.Storage
+	Logger    *logging.Logger
+	Chain     string
+	BatchSize int
+	Listeners []alchemy.BlockListener
+}
+
+func NewReprocessor(archive alchemy.Archive, db alchemy.Storage, chain string, batchSize int, logger *logging.Logger, listeners ...alchemy.BlockListener) *Reprocessor {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &Reprocessor{
+		Archive:   archive,
+		DB:        db,
+		Logger:    logger,
+		Chain:     chain,
+		BatchSize: batchSize,
+		Listeners: listeners,
+	}
+}
+
+func (r *Reprocessor) Run(ctx context.Context, from, to uint64) error {
+	var (
+		batch            []*alchemy.Block
+		saved, corrupted int
+	)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.DB.InsertBlocksBatch(ctx, batch, r.Chain); err != nil {
+			return err
+		}
+		// Слушатели (роллапы и т.п.) должны увидеть новые значения метрик
+		for _, l := range r.Listeners {
+			l.OnBlocksSaved(ctx, r.Chain, batch)
+		}
+		saved += len(batch)
+		batch = nil
+		return nil
+	}
+
+	err := r.Archive.Scan(ctx, r.Chain, from, to, func(raw *alchemy.RawBlock) error {
+		block, err := collect.NewBlockMetricsFromRaw(raw)
+		if err != nil {
+			r.Logger.Warnf("skip archived block %d: %v", raw.Number, err)
+			corrupted++
+			return nil
+		}
+
+		batch = append(batch, block)
+		if len(batch) < r.BatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return fmt.Errorf("reprocess %s [%d, %d]: %w", r.Chain, from, to, err)
+	}
+
+	r.Logger.Infof("reprocessed %d blocks of %s from archive, skipped %d", saved, r.Chain, corrupted)
+	return nil
+}
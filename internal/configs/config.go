@@ -2,7 +2,9 @@ package configs
 
 import (
 	"blocks_gas_validators/pkg/logging"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -12,42 +14,232 @@ type Config struct {
 	Listen  ListenConfig  `yaml:"listen"`
 	Storage StorageConfig `yaml:"storage"`
 	Alchemy AlchemyConfig `yaml:"alchemy"`
+	Mempool MempoolConfig `yaml:"mempool"`
+	Oracle  OracleConfig  `yaml:"oracle"`
+	GRPC    GRPCConfig    `yaml:"grpc"`
+	Health  HealthConfig  `yaml:"health"`
+	Rollups RollupsConfig `yaml:"rollups"`
+	Alerts  AlertsConfig  `yaml:"alerts"`
+	Export  ExportConfig  `yaml:"export"`
+	Import  ImportConfig  `yaml:"import"`
+	Archive ArchiveConfig `yaml:"archive"`
+	Sinks   SinksConfig   `yaml:"sinks"`
+	// Partitions - нарезка <chain>_block_metrics по времени
+	Partitions PartitionsConfig `yaml:"partitions"`
+	Migrations MigrationsConfig `yaml:"migrations"`
+	// Transactions - потранзакционная таблица <chain>_transactions рядом со сводкой по блокам
+	Transactions TransactionsConfig `yaml:"transactions"`
+	// Validators - таблица <chain>_validators с агрегатами по авторам блоков
+	Validators ValidatorsConfig `yaml:"validators"`
+	Verify     VerifyConfig     `yaml:"verify"`
 }
 
 type ListenConfig struct {
-	Type   string `yaml:"type"`
-	BindIP string `yaml:"bind_ip"`
-	Port   string `yaml:"port"`
+	Type       string `yaml:"type"`
+	BindIP     string `yaml:"bind_ip"`
+	Port       string `yaml:"port"`
+	SocketPath string `yaml:"socket_path" env-default:"miner.sock"`
 }
+
+// Переменные окружения MINER_* перекрывают значения из config.yaml
 type StorageConfig struct {
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
-	Database string `yaml:"database"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
+	Host     string `yaml:"host" env:"MINER_DB_HOST"`
+	Port     string `yaml:"port" env:"MINER_DB_PORT"`
+	Database string `yaml:"database" env:"MINER_DB_NAME"`
+	Username string `yaml:"username" env:"MINER_DB_USER"`
+	Password string `yaml:"password" env:"MINER_DB_PASSWORD"`
 }
 
 type AlchemyConfig struct {
-	Mode        string `yaml:"mode"`
-	NetworkName string `yaml:"network_name"`
+	Mode        string `yaml:"mode" env:"MINER_MODE"`
+	NetworkName string `yaml:"network_name" env:"MINER_CHAIN"`
 	NameApiKey  string `yaml:"name_api_key"`
-	Limiter     int    `yaml:"limiter"`
+	Limiter     int    `yaml:"limiter" env:"MINER_LIMITER"`
 	MaxRetries  int    `yaml:"max_retries"`
-	BatchSize   int    `yaml:"batch_size"`
-	Start       uint64 `yaml:"start"`
-	End         uint64 `yaml:"end"`
-	Workers     int    `yaml:"workers"`
+	BatchSize   int    `yaml:"batch_size" env:"MINER_BATCH_SIZE"`
+	Start       uint64 `yaml:"start" env:"MINER_START"`
+	End         uint64 `yaml:"end" env:"MINER_END"`
+	Workers     int    `yaml:"workers" env:"MINER_WORKERS"`
+	// StartTime/EndTime задают диапазон по времени [start_time, end_time) вместо номеров:
+	// RFC3339, дата 2006-01-02 (UTC) или unix секунды. Номера находятся бинарным поиском.
+	StartTime string `yaml:"start_time" env:"MINER_START_TIME"`
+	EndTime   string `yaml:"end_time" env:"MINER_END_TIME"`
+	// LastBlocks и Last - диапазон от текущего head: последние N блоков или последние 72h
+	LastBlocks uint64        `yaml:"last_blocks" env:"MINER_LAST_BLOCKS"`
+	Last       time.Duration `yaml:"last" env:"MINER_LAST"`
+	// BlockTimeCache - каталог кэша найденных (номер, время) точек, пусто - только в памяти
+	BlockTimeCache string `yaml:"block_time_cache" env-default:"cache"`
+}
+
+// ExportConfig - выгрузка для mode: export. Диапазон задаётся как у history: номерами
+// alchemy.start..alchemy.end или временем alchemy.start_time/end_time/last по block_time.
+type ExportConfig struct {
+	Dir        string `yaml:"dir" env-default:"export"`
+	Format     string `yaml:"format" env-default:"csv"`
+	SplitByDay bool   `yaml:"split_by_day"`
+}
+
+// VerifyConfig - проверка alchemy.start..alchemy.end в mode: verify (end 0 - до последнего
+// сохранённого блока). compare: none, sample (доля sample_rate блоков) или all - сверка с RPC.
+// С repair пропуски, дубли, подозрительные и расходящиеся с RPC блоки перезаписываются.
+type VerifyConfig struct {
+	Compare    string  `yaml:"compare" env-default:"sample"`
+	SampleRate float64 `yaml:"sample_rate" env-default:"0.01"`
+	Repair     bool    `yaml:"repair"`
+	// Report - путь для JSON отчёта, пусто - только в лог
+	Report string `yaml:"report"`
+}
+
+// ImportConfig - файлы для mode: import (glob шаблоны). .csv читается как CSV export,
+// остальное как JSONL с alchemy.Block или сырыми блоками eth_getBlockByNumber.
+type ImportConfig struct {
+	Files []string `yaml:"files"`
+}
+
+// ArchiveConfig - архив сырых ответов RPC. Пишется при сборе, если enabled,
+// и читается в mode: reprocess для блоков alchemy.start..alchemy.end.
+type ArchiveConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Dir         string `yaml:"dir" env-default:"archive"`
+	ChunkBlocks uint64 `yaml:"chunk_blocks" env-default:"10000"`
+	// MaxOpenChunks - сколько чанков держать открытыми, history воркеры пишут вразнобой
+	MaxOpenChunks int `yaml:"max_open_chunks" env-default:"8"`
+}
+
+// SinksConfig - дополнительные получатели блоков помимо Postgres. У каждого своя очередь
+// на buffer пачек и retries попыток записи с паузой retry_delay, сбой одного не задерживает остальные.
+type SinksConfig struct {
+	Buffer     int             `yaml:"buffer" env-default:"1000"`
+	Retries    int             `yaml:"retries" env-default:"3"`
+	RetryDelay time.Duration   `yaml:"retry_delay" env-default:"1s"`
+	Stdout     bool            `yaml:"stdout"`
+	JSONL      JSONLSinkConfig `yaml:"jsonl"`
+	NATS       NATSSinkConfig  `yaml:"nats"`
+}
+
+type JSONLSinkConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Dir     string `yaml:"dir" env-default:"sink"`
+}
+
+type NATSSinkConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	URL     string `yaml:"url" env-default:"nats://127.0.0.1:4222"`
+	// Subject - префикс, блоки публикуются в <subject>.<chain>.blocks
+	Subject string `yaml:"subject" env-default:"miner"`
+}
+
+// PartitionsConfig - партиции создаются на ahead периодов вперёд каждые check_interval.
+// Партиции старше retention (0 - хранить всё) отключаются (detach), удаляются (drop)
+// или переносятся в схему archive_schema (archive).
+type PartitionsConfig struct {
+	Granularity     string                           `yaml:"granularity" env-default:"day"`
+	Timezone        string                           `yaml:"timezone" env-default:"UTC"`
+	Ahead           int                              `yaml:"ahead" env-default:"2"`
+	CheckInterval   time.Duration                    `yaml:"check_interval" env-default:"1h"`
+	Retention       time.Duration                    `yaml:"retention"`
+	RetentionAction string                           `yaml:"retention_action" env-default:"detach"`
+	ArchiveSchema   string                           `yaml:"archive_schema" env-default:"archive"`
+	Chains          map[string]ChainPartitionsConfig `yaml:"chains"`
+}
+
+// MigrationsConfig - при auto встроенные миграции применяются при каждом старте майнера,
+// иначе только в mode: migrate
+type MigrationsConfig struct {
+	Auto bool `yaml:"auto" env-default:"true"`
+}
+
+type TransactionsConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ChainPartitionsConfig переопределяет общие настройки для одной сети
+type ChainPartitionsConfig struct {
+	Granularity string        `yaml:"granularity"`
+	Retention   time.Duration `yaml:"retention"`
+}
+
+type MempoolConfig struct {
+	Window           time.Duration `yaml:"window" env-default:"5m"`
+	SnapshotInterval time.Duration `yaml:"snapshot_interval" env-default:"15s"`
+}
+
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Listen без type - gRPC делит сокет с HTTP API
+	Listen ListenConfig `yaml:"listen"`
+}
+
+// HealthConfig задаёт пороги зависания в единицах BlockTime сети
+type HealthConfig struct {
+	HeadStallBlocks   float64       `yaml:"head_stall_blocks" env-default:"10"`
+	InsertStallBlocks float64       `yaml:"insert_stall_blocks" env-default:"30"`
+	StartupGrace      time.Duration `yaml:"startup_grace" env-default:"1m"`
+}
+
+// RollupsConfig задаёт, как часто пересчитываются грязные бакеты каждой гранулярности
+type RollupsConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	MinuteInterval time.Duration `yaml:"minute_interval" env-default:"15s"`
+	HourInterval   time.Duration `yaml:"hour_interval" env-default:"5m"`
+	DayInterval    time.Duration `yaml:"day_interval" env-default:"30m"`
+}
+
+type ValidatorsConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+type AlertsConfig struct {
+	Enabled       bool              `yaml:"enabled"`
+	CheckInterval time.Duration     `yaml:"check_interval" env-default:"10s"`
+	Cooldown      time.Duration     `yaml:"cooldown" env-default:"10m"`
+	Webhooks      []WebhookConfig   `yaml:"webhooks"`
+	Rules         []AlertRuleConfig `yaml:"rules"`
+}
+
+type WebhookConfig struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// AlertRuleConfig описывает правило. type: block (по умолчанию) - условие metric op threshold
+// на for_blocks блоков подряд; no_head / no_insert - нет событий for_block_times * BlockTime.
+type AlertRuleConfig struct {
+	Name          string  `yaml:"name"`
+	Type          string  `yaml:"type"`
+	Metric        string  `yaml:"metric"`
+	Op            string  `yaml:"op"`
+	Threshold     float64 `yaml:"threshold"`
+	ForBlocks     int     `yaml:"for_blocks"`
+	ForBlockTimes float64 `yaml:"for_block_times"`
+}
+
+type OracleConfig struct {
+	SampleBlocks int `yaml:"sample_blocks" env-default:"20"`
 }
 
 var instance *Config
 var once sync.Once
 
+// DefaultPath - config.yaml в текущей директории, если не задан MINER_CONFIG
+func DefaultPath() string {
+	if path := os.Getenv("MINER_CONFIG"); path != "" {
+		return path
+	}
+	return "config.yaml"
+}
+
 func GetConfig() *Config {
+	return LoadConfig(DefaultPath())
+}
+
+// LoadConfig читает конфиг из path один раз за процесс, следующие вызовы возвращают его же
+func LoadConfig(path string) *Config {
 	once.Do(func() {
 		logger := logging.GetLogger()
-		logger.Info("read application configuration")
+		logger.Infof("read application configuration from %s", path)
 		instance = &Config{}
-		if err := cleanenv.ReadConfig("config.yaml", instance); err != nil {
+		if err := cleanenv.ReadConfig(path, instance); err != nil {
 			help, _ := cleanenv.GetDescription(instance, nil)
 			logger.Info(help)
 			logger.Fatal(err)
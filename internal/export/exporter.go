@@ -0,0 +1,134 @@
+package export
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Exporter выгружает блоки сети из базы в файлы. Блоки читаются потоком,
+// в памяти держится только текущий блок и буфер writer'а.
+type Exporter struct {
+	Reader     alchemy.Reader
+	Logger     *logging.Logger
+	Chain      string
+	Dir        string
+	Format     string
+	SplitByDay bool
+
+	file    *os.File
+	writer  BlockWriter
+	day     string
+	written int
+	files   []string
+}
+
+func NewExporter(reader alchemy.Reader, chain, dir, format string, splitByDay bool, logger *logging.Logger) *Exporter {
+	return &Exporter{
+		Reader:     reader,
+		Logger:     logger,
+		Chain:      chain,
+		Dir:        dir,
+		Format:     format,
+		SplitByDay: splitByDay,
+	}
+}
+
+func (e *Exporter) ExportRange(ctx context.Context, from, to uint64) error {
+	name := fmt.Sprintf("%s_%d_%d", e.Chain, from, to)
+	return e.run(name, func(fn func(*alchemy.Block) error) error {
+		return e.Reader.StreamBlocksByRange(ctx, e.Chain, from, to, fn)
+	})
+}
+
+func (e *Exporter) ExportTime(ctx context.Context, from, to time.Time) error {
+	name := fmt.Sprintf("%s_%s_%s", e.Chain, from.UTC().Format("20060102T150405"), to.UTC().Format("20060102T150405"))
+	return e.run(name, func(fn func(*alchemy.Block) error) error {
+		return e.Reader.StreamBlocksByTime(ctx, e.Chain, from, to, fn)
+	})
+}
+
+func (e *Exporter) run(name string, stream func(fn func(*alchemy.Block) error) error) error {
+	// Проверяем формат до создания файлов, а не на первом блоке
+	switch e.Format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+	default:
+		return fmt.Errorf("unknown export format: %s", e.Format)
+	}
+	if err := os.MkdirAll(e.Dir, 0o755); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+
+	err := stream(func(block *alchemy.Block) error {
+		target := name
+		if e.SplitByDay {
+			target = fmt.Sprintf("%s_%s", e.Chain, block.BlockTime.UTC().Format("2006-01-02"))
+		}
+		if e.writer == nil || target != e.day {
+			if err := e.rotate(target); err != nil {
+				return err
+			}
+		}
+
+		if err := e.writer.Write(block); err != nil {
+			return fmt.Errorf("write block %d: %w", block.BlockNumber, err)
+		}
+		e.written++
+		return nil
+	})
+
+	if closeErr := e.closeFile(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("export %s: %w", e.Chain, err)
+	}
+
+	e.Logger.Infof("exported %d blocks of %s to %d file(s) in %s", e.written, e.Chain, len(e.files), e.Dir)
+	return nil
+}
+
+// rotate закрывает текущий файл и открывает файл для следующего дня.
+// Выборка отсортирована по времени, поэтому каждый день открывается один раз.
+func (e *Exporter) rotate(name string) error {
+	if err := e.closeFile(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(e.Dir, fmt.Sprintf("%s.%s", name, e.Format))
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+
+	writer, err := NewBlockWriter(e.Format, file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	e.file, e.writer, e.day = file, writer, name
+	e.files = append(e.files, path)
+	e.Logger.Infof("writing %s", path)
+	return nil
+}
+
+func (e *Exporter) closeFile() error {
+	if e.writer == nil {
+		return nil
+	}
+
+	err := e.writer.Close()
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	e.file, e.writer = nil, nil
+	if err != nil {
+		return fmt.Errorf("close export file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package export
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+
+	// parquetRowGroup ограничивает сколько строк parquet держит в памяти до сброса на диск
+	parquetRowGroup = 10000
+)
+
+// BlockWriter пишет блоки в файл одного формата
+type BlockWriter interface {
+	Write(block *alchemy.Block) error
+	// Close дописывает буферы и футер, но не закрывает нижележащий файл
+	Close() error
+}
+
+func NewBlockWriter(format string, w io.Writer) (BlockWriter, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return newJSONLWriter(w), nil
+	case FormatParquet:
+		return newParquetWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// CSVHeader - колонки в порядке таблицы <chain>_block_metrics
+var CSVHeader = []string{
+	"block_number", "block_time", "transactions_count", "block_size_bytes", "gas_limit", "gas_used",
+	"block_fullness", "block_author", "gas_min", "gas_max", "gas_avg", "gas_stddev", "gas_all_prices",
+	"block_timestamp", "unknown_tx_count", "base_fee", "l1_fee_total", "l1_fee_avg", "l1_gas_used",
+	"l1_base_fee_scalar", "l1_blob_base_fee_scalar", "deposit_tx_count",
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return nil, fmt.Errorf("write csv header: %w", err)
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(block *alchemy.Block) error {
+	// gas_all_prices пишем JSON массивом в одну ячейку, как он лежит в jsonb
+	prices, err := json.Marshal(block.GasStats.AllPrices)
+	if err != nil {
+		return fmt.Errorf("marshal gas prices: %w", err)
+	}
+
+	record := []string{
+		strconv.FormatUint(block.BlockNumber, 10),
+		block.BlockTime.UTC().Format(time.RFC3339),
+		strconv.Itoa(block.TransactionsCount),
+		strconv.FormatUint(block.BlockSizeBytes, 10),
+		strconv.FormatUint(block.GasLimit, 10),
+		strconv.FormatUint(block.GasUsed, 10),
+		formatFloat(block.BlockFullness),
+		block.Validator,
+		formatFloat(block.GasStats.Min),
+		formatFloat(block.GasStats.Max),
+		formatFloat(block.GasStats.Avg),
+		formatFloat(block.GasStats.Stddev),
+		string(prices),
+		strconv.FormatUint(block.BlockTimestamp, 10),
+		strconv.Itoa(block.UnknownTxCount),
+		formatFloat(block.BaseFee),
+		"", "", "", "", "", "",
+	}
+
+	if l1 := block.L1Fees; l1 != nil {
+		record[16] = formatFloat(l1.Total)
+		record[17] = formatFloat(l1.Avg)
+		record[18] = strconv.FormatUint(l1.GasUsed, 10)
+		record[19] = strconv.FormatUint(l1.BaseFeeScalar, 10)
+		record[20] = strconv.FormatUint(l1.BlobBaseFeeScalar, 10)
+		record[21] = strconv.Itoa(l1.DepositCount)
+	}
+
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (j *jsonlWriter) Write(block *alchemy.Block) error {
+	return j.enc.Encode(block)
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.buf.Flush()
+}
+
+// parquetBlock - плоская схема для parquet, L1 колонки пустые у не OP-stack сетей
+type parquetBlock struct {
+	BlockNumber         uint64    `parquet:"block_number"`
+	BlockTime           time.Time `parquet:"block_time,timestamp(millisecond)"`
+	TransactionsCount   int64     `parquet:"transactions_count"`
+	BlockSizeBytes      uint64    `parquet:"block_size_bytes"`
+	GasLimit            uint64    `parquet:"gas_limit"`
+	GasUsed             uint64    `parquet:"gas_used"`
+	BlockFullness       float64   `parquet:"block_fullness"`
+	BlockAuthor         string    `parquet:"block_author,dict"`
+	GasMin              float64   `parquet:"gas_min"`
+	GasMax              float64   `parquet:"gas_max"`
+	GasAvg              float64   `parquet:"gas_avg"`
+	GasStddev           float64   `parquet:"gas_stddev"`
+	GasAllPrices        []float64 `parquet:"gas_all_prices,list"`
+	BlockTimestamp      uint64    `parquet:"block_timestamp"`
+	UnknownTxCount      int64     `parquet:"unknown_tx_count"`
+	BaseFee             float64   `parquet:"base_fee"`
+	L1FeeTotal          *float64  `parquet:"l1_fee_total,optional"`
+	L1FeeAvg            *float64  `parquet:"l1_fee_avg,optional"`
+	L1GasUsed           *uint64   `parquet:"l1_gas_used,optional"`
+	L1BaseFeeScalar     *uint64   `parquet:"l1_base_fee_scalar,optional"`
+	L1BlobBaseFeeScalar *uint64   `parquet:"l1_blob_base_fee_scalar,optional"`
+	DepositTxCount      *int64    `parquet:"deposit_tx_count,optional"`
+}
+
+type parquetWriter struct {
+	w   *parquet.GenericWriter[parquetBlock]
+	row [1]parquetBlock
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{
+		w: parquet.NewGenericWriter[parquetBlock](w, parquet.MaxRowsPerRowGroup(parquetRowGroup)),
+	}
+}
+
+func (p *parquetWriter) Write(block *alchemy.Block) error {
+	p.row[0] = parquetBlock{
+		BlockNumber:       block.BlockNumber,
+		BlockTime:         block.BlockTime.UTC(),
+		TransactionsCount: int64(block.TransactionsCount),
+		BlockSizeBytes:    block.BlockSizeBytes,
+		GasLimit:          block.GasLimit,
+		GasUsed:           block.GasUsed,
+		BlockFullness:     block.BlockFullness,
+		BlockAuthor:       block.Validator,
+		GasMin:            block.GasStats.Min,
+		GasMax:            block.GasStats.Max,
+		GasAvg:            block.GasStats.Avg,
+		GasStddev:         block.GasStats.Stddev,
+		GasAllPrices:      block.GasStats.AllPrices,
+		BlockTimestamp:    block.BlockTimestamp,
+		UnknownTxCount:    int64(block.UnknownTxCount),
+		BaseFee:           block.BaseFee,
+	}
+
+	if l1 := block.L1Fees; l1 != nil {
+		deposits := int64(l1.DepositCount)
+		p.row[0].L1FeeTotal = &l1.Total
+		p.row[0].L1FeeAvg = &l1.Avg
+		p.row[0].L1GasUsed = &l1.GasUsed
+		p.row[0].L1BaseFeeScalar = &l1.BaseFeeScalar
+		p.row[0].L1BlobBaseFeeScalar = &l1.BlobBaseFeeScalar
+		p.row[0].DepositTxCount = &deposits
+	}
+
+	_, err := p.w.Write(p.row[:])
+	return err
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
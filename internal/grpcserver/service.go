@@ -0,0 +1,289 @@
+package grpcserver
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/internal/server"
+	"blocks_gas_validators/internal/stream"
+	"blocks_gas_validators/pkg/api/minerpb"
+	"blocks_gas_validators/pkg/chains"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+	defaultWindow    = time.Hour
+	// streamBuffer - сколько блоков может накопиться у медленного клиента до потерь
+	streamBuffer = 256
+)
+
+type Service struct {
+	minerpb.UnimplementedMinerServiceServer
+
+	reader alchemy.Reader
+	hub    *stream.Hub
+	logger *logging.Logger
+}
+
+func NewService(reader alchemy.Reader, hub *stream.Hub, logger *logging.Logger) *Service {
+	return &Service{
+		reader: reader,
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+func NewServer(svc *Service) *grpc.Server {
+	srv := grpc.NewServer()
+	minerpb.RegisterMinerServiceServer(srv, svc)
+	return srv
+}
+
+func (s *Service) GetBlock(ctx context.Context, req *minerpb.GetBlockRequest) (*minerpb.Block, error) {
+	if err := checkChain(req.GetChain()); err != nil {
+		return nil, err
+	}
+
+	block, err := s.reader.BlockByNumber(ctx, req.GetChain(), req.GetBlockNumber())
+	if errors.Is(err, alchemy.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "block %d not found", req.GetBlockNumber())
+	}
+	if err != nil {
+		return nil, s.internal(err)
+	}
+	return toProtoBlock(req.GetChain(), block), nil
+}
+
+func (s *Service) ListBlocks(ctx context.Context, req *minerpb.ListBlocksRequest) (*minerpb.ListBlocksResponse, error) {
+	if err := checkChain(req.GetChain()); err != nil {
+		return nil, err
+	}
+	page := toPage(req.GetPage())
+
+	var blocks []*alchemy.Block
+	var err error
+	switch r := req.GetRange().(type) {
+	case *minerpb.ListBlocksRequest_Numbers:
+		if r.Numbers.GetTo() < r.Numbers.GetFrom() {
+			return nil, status.Error(codes.InvalidArgument, "range end is less than start")
+		}
+		blocks, err = s.reader.BlocksByRange(ctx, req.GetChain(), r.Numbers.GetFrom(), r.Numbers.GetTo(), page)
+	default:
+		from, to, rangeErr := toTimeRange(req.GetTime())
+		if rangeErr != nil {
+			return nil, rangeErr
+		}
+		blocks, err = s.reader.BlocksByTime(ctx, req.GetChain(), from, to, page)
+	}
+	if err != nil {
+		return nil, s.internal(err)
+	}
+
+	resp := &minerpb.ListBlocksResponse{NextOffset: nextOffset(len(blocks), page)}
+	for _, block := range blocks {
+		resp.Blocks = append(resp.Blocks, toProtoBlock(req.GetChain(), block))
+	}
+	return resp, nil
+}
+
+func (s *Service) GetGasStats(ctx context.Context, req *minerpb.GetGasStatsRequest) (*minerpb.GasStats, error) {
+	if err := checkChain(req.GetChain()); err != nil {
+		return nil, err
+	}
+
+	from, to, err := toTimeRange(req.GetTime())
+	if err != nil {
+		return nil, err
+	}
+	agg, err := s.reader.GasStatsByTime(ctx, req.GetChain(), from, to)
+	if err != nil {
+		return nil, s.internal(err)
+	}
+
+	return &minerpb.GasStats{
+		From:         timestamppb.New(agg.From),
+		To:           timestamppb.New(agg.To),
+		BlockCount:   agg.BlockCount,
+		TxCount:      agg.TxCount,
+		GasUsedTotal: agg.GasUsedTotal,
+		AvgFullness:  agg.AvgFullness,
+		GasMin:       agg.GasMin,
+		GasMax:       agg.GasMax,
+		GasAvg:       agg.GasAvg,
+		GasP50:       agg.GasP50,
+		GasP95:       agg.GasP95,
+		AvgBaseFee:   agg.BaseFee,
+	}, nil
+}
+
+func (s *Service) ListValidators(ctx context.Context, req *minerpb.ListValidatorsRequest) (*minerpb.ListValidatorsResponse, error) {
+	if err := checkChain(req.GetChain()); err != nil {
+		return nil, err
+	}
+	page := toPage(req.GetPage())
+
+	from, to, err := toTimeRange(req.GetTime())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.reader.ValidatorStatsByTime(ctx, req.GetChain(), from, to, page)
+	if err != nil {
+		return nil, s.internal(err)
+	}
+
+	resp := &minerpb.ListValidatorsResponse{NextOffset: nextOffset(len(stats), page)}
+	for _, v := range stats {
+		resp.Validators = append(resp.Validators, &minerpb.Validator{
+			Validator:   v.Validator,
+			Blocks:      v.Blocks,
+			FirstBlock:  v.FirstBlock,
+			LastBlock:   v.LastBlock,
+			LastSeen:    timestamppb.New(v.LastSeen),
+			AvgFullness: v.AvgFullness,
+			AvgTxCount:  v.AvgTxCount,
+			AvgGasPrice: v.AvgGasPrice,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) StreamBlocks(req *minerpb.StreamBlocksRequest, srv grpc.ServerStreamingServer[minerpb.Block]) error {
+	wanted := make(map[string]bool)
+	for _, chain := range req.GetChains() {
+		if err := checkChain(chain); err != nil {
+			return err
+		}
+		wanted[chain] = true
+	}
+
+	sub := s.hub.Subscribe(streamBuffer, func(chain string, _ *alchemy.Block) bool {
+		return len(wanted) == 0 || wanted[chain]
+	})
+	defer s.hub.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := srv.Send(toProtoBlock(event.Chain, event.Block)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Service) internal(err error) error {
+	s.logger.Errorf("grpc request failed: %v", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func checkChain(chain string) error {
+	if _, ok := chains.AlchemyChains[chain]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown chain: %q", chain)
+	}
+	return nil
+}
+
+func toPage(p *minerpb.Page) alchemy.Page {
+	page := alchemy.Page{Limit: defaultPageLimit}
+	if p.GetLimit() > 0 {
+		page.Limit = min(int(p.GetLimit()), maxPageLimit)
+	}
+	if p.GetOffset() > 0 {
+		page.Offset = int(p.GetOffset())
+	}
+	return page
+}
+
+// nextOffset возвращает 0, если страница неполная и дальше данных нет
+func nextOffset(count int, page alchemy.Page) int32 {
+	if count < page.Limit {
+		return 0
+	}
+	return int32(page.Offset + page.Limit)
+}
+
+// toTimeRange по умолчанию отдаёт последний час. Пустой или перевёрнутый диапазон -
+// InvalidArgument, как и у HTTP API, а не пустой ответ.
+func toTimeRange(r *minerpb.TimeRange) (time.Time, time.Time, error) {
+	to := time.Now()
+	if r.GetTo() != nil {
+		to = r.GetTo().AsTime()
+	}
+	from := to.Add(-defaultWindow)
+	if r.GetFrom() != nil {
+		from = r.GetFrom().AsTime()
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "time range start %s is not before end %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+func toProtoBlock(chain string, block *alchemy.Block) *minerpb.Block {
+	pb := &minerpb.Block{
+		Chain:             chain,
+		BlockNumber:       block.BlockNumber,
+		BlockTime:         timestamppb.New(block.BlockTime),
+		BlockTimestamp:    block.BlockTimestamp,
+		TransactionsCount: int64(block.TransactionsCount),
+		UnknownTxCount:    int64(block.UnknownTxCount),
+		BlockSizeBytes:    block.BlockSizeBytes,
+		GasLimit:          block.GasLimit,
+		GasUsed:           block.GasUsed,
+		BlockFullness:     block.BlockFullness,
+		BaseFee:           block.BaseFee,
+		Validator:         block.Validator,
+		Gas: &minerpb.GasPrices{
+			Min:       block.GasStats.Min,
+			Max:       block.GasStats.Max,
+			Avg:       block.GasStats.Avg,
+			Stddev:    block.GasStats.Stddev,
+			AllPrices: block.GasStats.AllPrices,
+		},
+	}
+	if block.L1Fees != nil {
+		pb.L1Fees = &minerpb.L1Fees{
+			Total:             block.L1Fees.Total,
+			Avg:               block.L1Fees.Avg,
+			GasUsed:           block.L1Fees.GasUsed,
+			BaseFeeScalar:     block.L1Fees.BaseFeeScalar,
+			BlobBaseFeeScalar: block.L1Fees.BlobBaseFeeScalar,
+			DepositCount:      int64(block.L1Fees.DepositCount),
+		}
+	}
+	return pb
+}
+
+// Serve запускает gRPC на отдельном сокете до отмены контекста
+func Serve(ctx context.Context, srv *grpc.Server, cfg configs.ListenConfig, logger *logging.Logger) error {
+	listener, err := server.Listen(cfg)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	logger.Infof("grpc server listening on %s", listener.Addr())
+	if err := srv.Serve(listener); err != nil {
+		return fmt.Errorf("grpc server: %w", err)
+	}
+	return nil
+}
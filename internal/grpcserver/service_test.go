@@ -0,0 +1,47 @@
+package grpcserver
+
+import (
+	"blocks_gas_validators/pkg/api/minerpb"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestToTimeRange(t *testing.T) {
+	now := time.Now()
+	ts := func(d time.Duration) *timestamppb.Timestamp { return timestamppb.New(now.Add(d)) }
+
+	cases := []struct {
+		name    string
+		r       *minerpb.TimeRange
+		wantErr bool
+	}{
+		{name: "default window", r: nil},
+		{name: "only from", r: &minerpb.TimeRange{From: ts(-2 * time.Hour)}},
+		{name: "only to", r: &minerpb.TimeRange{To: ts(-2 * time.Hour)}},
+		{name: "from and to", r: &minerpb.TimeRange{From: ts(-2 * time.Hour), To: ts(-time.Hour)}},
+		{name: "from in the future", r: &minerpb.TimeRange{From: ts(time.Hour)}, wantErr: true},
+		{name: "reversed", r: &minerpb.TimeRange{From: ts(-time.Hour), To: ts(-2 * time.Hour)}, wantErr: true},
+		{name: "empty", r: &minerpb.TimeRange{From: ts(-time.Hour), To: ts(-time.Hour)}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := toTimeRange(tc.r)
+			if tc.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("err = %v, want InvalidArgument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("time range: %v", err)
+			}
+			if !from.Before(to) {
+				t.Fatalf("range [%s, %s) is empty", from, to)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const checkTimeout = 3 * time.Second
+
+// Probe - проверка доступности зависимости (Postgres, RPC)
+type Probe func(ctx context.Context) error
+
+type CheckResult struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Healthy          bool                   `json:"healthy"`
+	Chain            string                 `json:"chain"`
+	Checks           map[string]CheckResult `json:"checks,omitempty"`
+	LastHeadAgeSec   *float64               `json:"last_head_age_seconds,omitempty"`
+	LastInsertAgeSec *float64               `json:"last_insert_age_seconds,omitempty"`
+	Stalled          []string               `json:"stalled,omitempty"`
+}
+
+// Checker отслеживает время последнего head и последней вставки и считает майнер
+// зависшим, если они старше заданного числа BlockTime.
+type Checker struct {
+	chain       string
+	blockTime   time.Duration
+	cfg         configs.HealthConfig
+	expectHeads bool
+	// expectInserts выключается в режимах без сохранения блоков (mempool)
+	expectInserts bool
+	startedAt     time.Time
+	probes        map[string]Probe
+
+	lastHead   atomic.Int64
+	lastInsert atomic.Int64
+}
+
+func NewChecker(chain string, blockTime time.Duration, expectHeads, expectInserts bool, cfg configs.HealthConfig, probes map[string]Probe) *Checker {
+	return &Checker{
+		chain:         chain,
+		blockTime:     blockTime,
+		cfg:           cfg,
+		expectHeads:   expectHeads,
+		expectInserts: expectInserts,
+		startedAt:     time.Now(),
+		probes:        probes,
+	}
+}
+
+func (c *Checker) OnHead(_ uint64) {
+	c.lastHead.Store(time.Now().UnixNano())
+}
+
+func (c *Checker) OnBlocksSaved(_ context.Context, _ string, _ []*alchemy.Block) {
+	c.lastInsert.Store(time.Now().UnixNano())
+}
+
+// Liveness проверяет только собственное состояние процесса, без зависимостей
+func (c *Checker) Liveness() Report {
+	report := Report{Healthy: true, Chain: c.chain}
+	c.checkStall(&report, time.Now())
+	return report
+}
+
+// Readiness дополнительно проверяет Postgres и RPC
+func (c *Checker) Readiness(ctx context.Context) Report {
+	report := c.Liveness()
+	report.Checks = make(map[string]CheckResult, len(c.probes))
+
+	for name, probe := range c.probes {
+		probeCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := probe(probeCtx)
+		cancel()
+
+		if err != nil {
+			report.Healthy = false
+			report.Checks[name] = CheckResult{OK: false, Error: err.Error()}
+			continue
+		}
+		report.Checks[name] = CheckResult{OK: true}
+	}
+	return report
+}
+
+func (c *Checker) checkStall(report *Report, now time.Time) {
+	// Сразу после старта ещё нечего ждать
+	if now.Sub(c.startedAt) < c.cfg.StartupGrace {
+		return
+	}
+
+	if c.expectHeads {
+		age := c.age(&c.lastHead, now)
+		report.LastHeadAgeSec = &age
+		if age > c.cfg.HeadStallBlocks*c.blockTime.Seconds() {
+			report.Healthy = false
+			report.Stalled = append(report.Stalled, "head")
+		}
+	}
+
+	if c.expectInserts {
+		age := c.age(&c.lastInsert, now)
+		report.LastInsertAgeSec = &age
+		if age > c.cfg.InsertStallBlocks*c.blockTime.Seconds() {
+			report.Healthy = false
+			report.Stalled = append(report.Stalled, "insert")
+		}
+	}
+}
+
+// age считает от старта процесса, если событий ещё не было
+func (c *Checker) age(last *atomic.Int64, now time.Time) float64 {
+	ts := last.Load()
+	if ts == 0 {
+		return now.Sub(c.startedAt).Seconds()
+	}
+	return now.Sub(time.Unix(0, ts)).Seconds()
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"blocks_gas_validators/internal/export"
+	"blocks_gas_validators/internal/miner/alchemy"
+	collect "blocks_gas_validators/internal/miner/alchemy/collector"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// decodeJSONLine разбирает строку JSONL. Поддерживаются три вида записей:
+// alchemy.Block (как пишет export), сырой блок eth_getBlockByNumber
+// и полный JSON-RPC ответ с блоком в result.
+func decodeJSONLine(line []byte) (*alchemy.Block, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	if result, ok := probe["result"]; ok {
+		if string(result) == "null" {
+			return nil, fmt.Errorf("rpc response without block")
+		}
+		return decodeRawBlock(result)
+	}
+
+	if _, ok := probe["block_number"]; ok {
+		var block alchemy.Block
+		if err := json.Unmarshal(line, &block); err != nil {
+			return nil, fmt.Errorf("decode block: %w", err)
+		}
+		return &block, nil
+	}
+
+	if _, ok := probe["number"]; ok {
+		return decodeRawBlock(line)
+	}
+
+	return nil, fmt.Errorf("unknown record: neither block metrics nor rpc block")
+}
+
+// decodeRawBlock считает метрики из сырого блока. L1 комиссии OP-stack сетей
+// требуют квитанций, которых в дампе нет, поэтому для таких блоков они остаются пустыми.
+func decodeRawBlock(raw []byte) (*alchemy.Block, error) {
+	return collect.NewBlockMetricsFromRaw(&alchemy.RawBlock{Block: raw})
+}
+
+// csvDecoder разбирает CSV с заголовком в формате export. Колонки ищутся по имени,
+// поэтому порядок может отличаться, а L1 колонки - отсутствовать.
+type csvDecoder struct {
+	index map[string]int
+}
+
+func newCSVDecoder(header []string) (*csvDecoder, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+
+	for _, required := range export.CSVHeader[:16] {
+		if _, ok := index[required]; !ok {
+			return nil, fmt.Errorf("csv header misses column %s", required)
+		}
+	}
+	return &csvDecoder{index: index}, nil
+}
+
+func (d *csvDecoder) decode(record []string) (*alchemy.Block, error) {
+	var (
+		block alchemy.Block
+		err   error
+	)
+
+	get := func(name string) string {
+		i, ok := d.index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+	parseUint := func(name string, dst *uint64) {
+		if err == nil {
+			if *dst, err = strconv.ParseUint(get(name), 10, 64); err != nil {
+				err = fmt.Errorf("parse %s: %w", name, err)
+			}
+		}
+	}
+	parseInt := func(name string, dst *int) {
+		if err == nil {
+			if *dst, err = strconv.Atoi(get(name)); err != nil {
+				err = fmt.Errorf("parse %s: %w", name, err)
+			}
+		}
+	}
+	parseFloat := func(name string, dst *float64) {
+		if err == nil {
+			if *dst, err = strconv.ParseFloat(get(name), 64); err != nil {
+				err = fmt.Errorf("parse %s: %w", name, err)
+			}
+		}
+	}
+
+	parseUint("block_number", &block.BlockNumber)
+	parseUint("block_timestamp", &block.BlockTimestamp)
+	parseInt("transactions_count", &block.TransactionsCount)
+	parseInt("unknown_tx_count", &block.UnknownTxCount)
+	parseUint("block_size_bytes", &block.BlockSizeBytes)
+	parseUint("gas_limit", &block.GasLimit)
+	parseUint("gas_used", &block.GasUsed)
+	parseFloat("block_fullness", &block.BlockFullness)
+	parseFloat("base_fee", &block.BaseFee)
+	parseFloat("gas_min", &block.GasStats.Min)
+	parseFloat("gas_max", &block.GasStats.Max)
+	parseFloat("gas_avg", &block.GasStats.Avg)
+	parseFloat("gas_stddev", &block.GasStats.Stddev)
+	if err != nil {
+		return nil, err
+	}
+
+	block.Validator = get("block_author")
+	if block.BlockTime, err = time.Parse(time.RFC3339, get("block_time")); err != nil {
+		return nil, fmt.Errorf("parse block_time: %w", err)
+	}
+	if err := json.Unmarshal([]byte(get("gas_all_prices")), &block.GasStats.AllPrices); err != nil {
+		return nil, fmt.Errorf("parse gas_all_prices: %w", err)
+	}
+
+	if get("l1_fee_total") != "" {
+		var l1 alchemy.L1FeeStats
+		parseFloat("l1_fee_total", &l1.Total)
+		parseFloat("l1_fee_avg", &l1.Avg)
+		parseUint("l1_gas_used", &l1.GasUsed)
+		parseUint("l1_base_fee_scalar", &l1.BaseFeeScalar)
+		parseUint("l1_blob_base_fee_scalar", &l1.BlobBaseFeeScalar)
+		parseInt("deposit_tx_count", &l1.DepositCount)
+		if err != nil {
+			return nil, err
+		}
+		block.L1Fees = &l1
+	}
+
+	return &block, nil
+}
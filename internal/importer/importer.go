@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxLineSize - сырой блок со всеми транзакциями легко превышает стандартные 64KB bufio.Scanner
+const maxLineSize = 64 << 20
+
+// Importer читает дампы блоков из файлов и отдаёт их пачками в тот же канал,
+// что и history коллектор, поэтому запись идёт через обычный BlockSaver.
+type Importer struct {
+	Logger    *logging.Logger
+	Chain     string
+	BatchSize int
+
+	loc *time.Location
+}
+
+func NewImporter(chain string, batchSize int, logger *logging.Logger) *Importer {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		logger.Fatalf("failed to load location: %v", err)
+	}
+
+	return &Importer{
+		Logger:    logger,
+		Chain:     chain,
+		BatchSize: batchSize,
+		loc:       loc,
+	}
+}
+
+// Files раскрывает glob шаблоны из конфига в список файлов
+func Files(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func (i *Importer) Run(ctx context.Context, files []string) <-chan []*alchemy.Block {
+	out := make(chan []*alchemy.Block, 4)
+
+	go func() {
+		defer close(out)
+
+		var total int
+		for _, path := range files {
+			n, err := i.importFile(ctx, path, out)
+			total += n
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if err != nil {
+				i.Logger.Errorf("failed to import %s: %v", path, err)
+				continue
+			}
+			i.Logger.Infof("read %d blocks from %s", n, path)
+		}
+		i.Logger.Infof("import finished: %d blocks from %d file(s)", total, len(files))
+	}()
+
+	return out
+}
+
+func (i *Importer) importFile(ctx context.Context, path string, out chan<- []*alchemy.Block) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var (
+		batch []*alchemy.Block
+		count int
+	)
+	emit := func(block *alchemy.Block) error {
+		// Live блоки хранят время в московской зоне, приводим к ней же
+		block.BlockTime = block.BlockTime.In(i.loc)
+		batch = append(batch, block)
+		count++
+
+		if len(batch) < i.BatchSize {
+			return nil
+		}
+		return i.send(ctx, out, &batch)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = i.readCSV(file, path, emit)
+	} else {
+		err = i.readJSONL(file, path, emit)
+	}
+	if err != nil {
+		return count, err
+	}
+
+	return count, i.send(ctx, out, &batch)
+}
+
+func (i *Importer) send(ctx context.Context, out chan<- []*alchemy.Block, batch *[]*alchemy.Block) error {
+	if len(*batch) == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case out <- *batch:
+		*batch = nil
+		return nil
+	}
+}
+
+// readJSONL и readCSV пропускают битые записи с предупреждением, чтобы одна строка не срывала весь дамп
+func (i *Importer) readJSONL(r io.Reader, path string, emit func(*alchemy.Block) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1<<20), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+
+		block, err := decodeJSONLine(data)
+		if err != nil {
+			i.Logger.Warnf("%s:%d: skip record: %v", path, line, err)
+			continue
+		}
+		if err := emit(block); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (i *Importer) readCSV(r io.Reader, path string, emit func(*alchemy.Block) error) error {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("read csv header: %w", err)
+	}
+	decoder, err := newCSVDecoder(header)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read csv: %w", err)
+		}
+
+		block, err := decoder.decode(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			i.Logger.Warnf("%s:%d: skip record: %v", path, line, err)
+			continue
+		}
+		if err := emit(block); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "miner"
+
+var (
+	RPCCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_calls_total",
+		Help:      "RPC calls to the node by method and status.",
+	}, []string{"chain", "method", "status"})
+
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_call_duration_seconds",
+		Help:      "RPC call latency by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"chain", "method"})
+
+	LimiterWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rate_limiter_wait_seconds",
+		Help:      "Time spent waiting for the RPC rate limiter.",
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"chain"})
+
+	BlocksFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_fetched_total",
+		Help:      "Blocks fetched and decoded from the node.",
+	}, []string{"chain"})
+
+	BlocksSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_saved_total",
+		Help:      "Blocks persisted to storage.",
+	}, []string{"chain"})
+
+	BlocksFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_failed_total",
+		Help:      "Blocks lost at the fetch or save stage.",
+	}, []string{"chain", "stage"})
+
+	InsertDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_insert_duration_seconds",
+		Help:      "Insert latency by write path (single, batch, copy).",
+		Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"chain", "method"})
+
+	ChannelDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "history_channel_depth",
+		Help:      "Buffered items in history pipeline channels.",
+	}, []string{"chain", "channel"})
+
+	ChainHead = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_block",
+		Help:      "Latest head block number received from the subscription.",
+	}, []string{"chain"})
+
+	LastSavedBlock = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_saved_block",
+		Help:      "Highest block number saved by the live saver.",
+	}, []string{"chain"})
+
+	SinkWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sink_writes_total",
+		Help:      "Block batches written to each output sink by status.",
+	}, []string{"chain", "sink", "status"})
+
+	SinkDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sink_dropped_blocks_total",
+		Help:      "Blocks dropped by a buffered sink after a full queue or exhausted retries.",
+	}, []string{"chain", "sink"})
+
+	SinkQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sink_queue_depth",
+		Help:      "Batches waiting in a buffered sink queue.",
+	}, []string{"sink"})
+
+	MempoolDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mempool_dropped_total",
+		Help:      "Pending transactions dropped before the mempool window: full lookup queue or full output channel.",
+	}, []string{"chain", "reason"})
+
+	HeadLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "live_head_lag_blocks",
+		Help:      "Chain head minus the last saved block in live mode.",
+	}, []string{"chain"})
+)
+
+// heads хранит последние head/saved по сетям, чтобы пересчитывать lag из обеих точек
+var (
+	headsMu sync.Mutex
+	heads   = make(map[string]*headState)
+)
+
+type headState struct {
+	head  uint64
+	saved uint64
+}
+
+func stateFor(chain string) *headState {
+	st, ok := heads[chain]
+	if !ok {
+		st = &headState{}
+		heads[chain] = st
+	}
+	return st
+}
+
+func updateLag(chain string, st *headState) {
+	if st.head == 0 || st.saved == 0 {
+		return
+	}
+	HeadLag.WithLabelValues(chain).Set(float64(st.head) - float64(st.saved))
+}
+
+func ObserveHead(chain string, number uint64) {
+	headsMu.Lock()
+	defer headsMu.Unlock()
+
+	st := stateFor(chain)
+	if number > st.head {
+		st.head = number
+		ChainHead.WithLabelValues(chain).Set(float64(number))
+	}
+	updateLag(chain, st)
+}
+
+func ObserveSaved(chain string, number uint64) {
+	headsMu.Lock()
+	defer headsMu.Unlock()
+
+	st := stateFor(chain)
+	if number > st.saved {
+		st.saved = number
+		LastSavedBlock.WithLabelValues(chain).Set(float64(number))
+	}
+	updateLag(chain, st)
+}
+
+// ObserveRPC записывает вызов RPC метода; err == nil считается успехом
+func ObserveRPC(chain, method string, started time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	RPCCalls.WithLabelValues(chain, method, status).Inc()
+	RPCDuration.WithLabelValues(chain, method).Observe(time.Since(started).Seconds())
+}
+
+func ObserveInsert(chain, method string, started time.Time) {
+	InsertDuration.WithLabelValues(chain, method).Observe(time.Since(started).Seconds())
+}
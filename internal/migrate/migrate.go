@@ -0,0 +1,272 @@
+package migrate
+
+import (
+	"blocks_gas_validators/migrations"
+	"blocks_gas_validators/pkg/chains"
+	"blocks_gas_validators/pkg/client/postgresql"
+	"blocks_gas_validators/pkg/logging"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lockKey - ключ advisory lock, чтобы несколько майнеров не мигрировали базу одновременно
+const lockKey = 7_294_311_001
+
+var (
+	fileRe  = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+	chainRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+type migration struct {
+	version uint64
+	name    string
+	sql     string
+}
+
+// Runner применяет встроенные миграции. Глобальные версии хранятся в schema_migrations
+// в формате golang-migrate (одна строка version, dirty), поэтому базы, которые раньше
+// мигрировали CLI, подхватываются без повторного применения.
+// Версии шаблонов сетей - в chain_schema_migrations, по строке на сеть.
+type Runner struct {
+	client postgresql.Client
+	logger *logging.Logger
+}
+
+func NewRunner(client postgresql.Client, logger *logging.Logger) *Runner {
+	return &Runner{client: client, logger: logger}
+}
+
+func load(fsys fs.FS, dir string) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var list []migration
+	for _, entry := range entries {
+		match := fileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad migration version %s: %w", entry.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+		}
+		list = append(list, migration{version: version, name: match[2], sql: string(data)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].version < list[j].version })
+	for i := 1; i < len(list); i++ {
+		if list[i].version == list[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %d", list[i].version)
+		}
+	}
+	return list, nil
+}
+
+// bootstrap создаёт таблицы версий. Тоже под блокировкой: параллельный
+// CREATE TABLE IF NOT EXISTS падает на уникальности pg_type.
+func (r *Runner) bootstrap(ctx context.Context) error {
+	tx, err := r.client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin migration: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS chain_schema_migrations (
+			chain TEXT NOT NULL PRIMARY KEY,
+			version BIGINT NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create migration tables: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// Up применяет глобальные миграции, затем шаблоны для каждой из сетей
+func (r *Runner) Up(ctx context.Context, networks ...string) error {
+	if err := r.bootstrap(ctx); err != nil {
+		return err
+	}
+
+	global, err := load(migrations.Global, ".")
+	if err != nil {
+		return err
+	}
+	for _, m := range global {
+		if err := r.apply(ctx, m, globalVersion, setGlobalVersion); err != nil {
+			return err
+		}
+	}
+
+	for _, chain := range networks {
+		if err := r.upChain(ctx, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runner) upChain(ctx context.Context, chain string) error {
+	// Имя сети попадает в имена таблиц, поэтому только известные сети с безопасным именем
+	if _, ok := chains.AlchemyChains[chain]; !ok || !chainRe.MatchString(chain) {
+		return fmt.Errorf("unknown chain %q", chain)
+	}
+
+	templates, err := load(migrations.Chain, "chain")
+	if err != nil {
+		return err
+	}
+
+	for _, m := range templates {
+		tmpl, err := template.New(m.name).Option("missingkey=error").Parse(m.sql)
+		if err != nil {
+			return fmt.Errorf("parse chain migration %d_%s: %w", m.version, m.name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, struct{ Chain string }{Chain: chain}); err != nil {
+			return fmt.Errorf("render chain migration %d_%s: %w", m.version, m.name, err)
+		}
+		m.sql = buf.String()
+		m.name = fmt.Sprintf("%s/%s", chain, m.name)
+
+		current := func(ctx context.Context, tx pgx.Tx) (uint64, error) {
+			return chainVersion(ctx, tx, chain)
+		}
+		set := func(ctx context.Context, tx pgx.Tx, version uint64) error {
+			return setChainVersion(ctx, tx, chain, version)
+		}
+		if err := r.apply(ctx, m, current, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply выполняет одну миграцию в своей транзакции под advisory lock.
+// Версию перечитываем уже под блокировкой: её мог поднять другой экземпляр.
+func (r *Runner) apply(
+	ctx context.Context,
+	m migration,
+	current func(context.Context, pgx.Tx) (uint64, error),
+	set func(context.Context, pgx.Tx, uint64) error,
+) error {
+	tx, err := r.client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin migration: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+
+	version, err := current(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if m.version <= version {
+		return nil
+	}
+
+	r.logger.Infof("applying migration %06d_%s", m.version, m.name)
+	if _, err := tx.Exec(ctx, m.sql); err != nil {
+		return fmt.Errorf("migration %06d_%s: %w", m.version, m.name, err)
+	}
+	if err := set(ctx, tx, m.version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit migration %06d_%s: %w", m.version, m.name, err)
+	}
+	return nil
+}
+
+var ErrDirty = errors.New("database schema is dirty")
+
+func globalVersion(ctx context.Context, tx pgx.Tx) (uint64, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := tx.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	// dirty оставляет golang-migrate после упавшей миграции, чинится только руками
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d", ErrDirty, version)
+	}
+	return uint64(version), nil
+}
+
+func setGlobalVersion(ctx context.Context, tx pgx.Tx, version uint64) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("reset schema version: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", int64(version)); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
+
+func chainVersion(ctx context.Context, tx pgx.Tx, chain string) (uint64, error) {
+	var version int64
+	err := tx.QueryRow(ctx, "SELECT version FROM chain_schema_migrations WHERE chain = $1", chain).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read %s schema version: %w", chain, err)
+	}
+	return uint64(version), nil
+}
+
+func setChainVersion(ctx context.Context, tx pgx.Tx, chain string, version uint64) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO chain_schema_migrations (chain, version) VALUES ($1, $2)
+		ON CONFLICT (chain) DO UPDATE SET version = EXCLUDED.version, updated_at = now()
+	`, chain, int64(version))
+	if err != nil {
+		return fmt.Errorf("set %s schema version: %w", chain, err)
+	}
+	return nil
+}
+
+// Chains возвращает все известные сети, для mode: migrate без конкретной сети
+func Chains() []string {
+	names := make([]string, 0, len(chains.AlchemyChains))
+	for name := range chains.AlchemyChains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
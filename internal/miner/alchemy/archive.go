@@ -0,0 +1,23 @@
+package alchemy
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RawBlock - ответы RPC для блока как есть. По ним можно пересчитать метрики
+// после изменения их определения, не запрашивая блоки заново.
+type RawBlock struct {
+	Number uint64          `json:"number"`
+	Block  json.RawMessage `json:"block"`
+	// Receipts есть только у OP-stack сетей, из них считаются L1 комиссии
+	Receipts json.RawMessage `json:"receipts,omitempty"`
+}
+
+type Archive interface {
+	Put(chain string, raw *RawBlock) error
+	// Scan вызывает fn для каждого блока архива в [from, to]. Порядок внутри чанка -
+	// порядок загрузки, один блок может встретиться несколько раз.
+	Scan(ctx context.Context, chain string, from, to uint64, fn func(*RawBlock) error) error
+	Close() error
+}
@@ -9,4 +9,12 @@ type Collector interface {
 	CollectBlockByNumber(ctx context.Context, blockNumber uint64) (*Block, error)
 	SubscribeNewBlocks(ctx context.Context, maxRetries int) (<-chan *Block, error)
 	CollectHistoryBlocksBatch(ctx context.Context, cfg configs.AlchemyConfig) <-chan []*Block
+	SubscribePendingTransactions(ctx context.Context) (<-chan *PendingTx, error)
+	// SubscribeIncludedTransactions отдаёт хэши транзакций каждого нового блока
+	SubscribeIncludedTransactions(ctx context.Context) (<-chan []string, error)
+}
+
+// HeadListener получает номер каждого нового head из подписки
+type HeadListener interface {
+	OnHead(number uint64)
 }
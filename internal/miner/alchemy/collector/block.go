@@ -2,83 +2,49 @@ package collect
 
 import (
 	"blocks_gas_validators/internal/miner/alchemy"
+	"encoding/json"
 	"fmt"
 	"math"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/core/types"
 )
 
-func NewBlockMetrics(block *types.Block) alchemy.Block {
-	txns := block.Transactions()
-	gasStats := CalculateGasStats(txns)
-	loc, _ := time.LoadLocation("Europe/Moscow")
-
-	t := time.Unix(int64(block.Time()), 0).In(loc)
-	return alchemy.Block{
-		BlockNumber:       block.NumberU64(),
-		BlockTime:         t,
-		BlockTimestamp:    block.Time(),
-		TransactionsCount: len(txns),
-		BlockSizeBytes:    block.Size(),
-		GasLimit:          block.GasLimit(),
-		GasUsed:           block.GasUsed(),
-		BlockFullness:     float64(block.GasUsed()) / float64(block.GasLimit()) * 100,
-		Validator:         block.Coinbase().Hex(),
-		GasStats:          gasStats,
-	}
+// knownTxTypes - типы транзакций, цены которых мы умеем интерпретировать.
+// Остальные (будущие типы, экзотика отдельных сетей) только считаются.
+var knownTxTypes = map[string]bool{
+	"":            true, // старые ноды не отдают type для legacy транзакций
+	"0x0":         true,
+	"0x1":         true,
+	"0x2":         true,
+	"0x3":         true,
+	"0x4":         true,
+	depositTxType: true,
 }
 
-func CalculateGasStats(transactions types.Transactions) alchemy.GasStats {
-	const weiToGwei = 1e9 // 1 gwei = 10^9 wei
-
-	if len(transactions) == 0 {
-		return alchemy.GasStats{
-			Min:       0.0,
-			Max:       0.0,
-			Avg:       0.0,
-			Stddev:    0.0,
-			AllPrices: []float64{},
-		}
+// NewBlockMetricsFromRaw считает метрики из сырых ответов RPC. Так считаются и свежие блоки,
+// и блоки из архива, поэтому пересчёт даёт тот же результат, что и сбор.
+func NewBlockMetricsFromRaw(raw *alchemy.RawBlock) (*alchemy.Block, error) {
+	var jsonBlock alchemy.JSONBlock
+	if err := json.Unmarshal(raw.Block, &jsonBlock); err != nil {
+		return nil, fmt.Errorf("failed to decode block: %w", err)
 	}
 
-	var gasPrices []float64
-	var min, max float64
-	var sum float64
-
-	for _, tx := range transactions {
-		price := tx.GasPrice().Int64()
-		priceGwei := float64(price) / weiToGwei
-
-		gasPrices = append(gasPrices, priceGwei)
-
-		if len(gasPrices) == 1 || priceGwei < min {
-			min = priceGwei
-		}
-		if len(gasPrices) == 1 || priceGwei > max {
-			max = priceGwei
-		}
-
-		sum += priceGwei
+	block, err := NewBlockMetricsFromJSON(jsonBlock)
+	if err != nil {
+		return nil, err
 	}
 
-	avg := sum / float64(len(transactions))
-
-	var sumSq float64
-	for _, g := range gasPrices {
-		diff := g - avg
-		sumSq += diff * diff
+	if len(raw.Receipts) > 0 {
+		var receipts []alchemy.JSONReceipt
+		if err := json.Unmarshal(raw.Receipts, &receipts); err != nil {
+			return nil, fmt.Errorf("failed to decode receipts: %w", err)
+		}
+		l1Fees := CalculateL1FeeStats(receipts)
+		block.L1Fees = &l1Fees
 	}
-	stddev := math.Sqrt(sumSq / float64(len(gasPrices)))
 
-	return alchemy.GasStats{
-		Min:       min,
-		Max:       max,
-		Avg:       avg,
-		Stddev:    stddev,
-		AllPrices: gasPrices,
-	}
+	return &block, nil
 }
 
 func NewBlockMetricsFromJSON(jsonBlock alchemy.JSONBlock) (alchemy.Block, error) {
@@ -108,6 +74,16 @@ func NewBlockMetricsFromJSON(jsonBlock alchemy.JSONBlock) (alchemy.Block, error)
 		return alchemy.Block{}, fmt.Errorf("failed to parse gas used: %w", err)
 	}
 
+	// baseFeePerGas есть только у сетей после London, до него остаётся 0
+	var baseFee float64
+	if jsonBlock.BaseFee != "" {
+		fee, err := hexutil.DecodeBig(jsonBlock.BaseFee)
+		if err != nil {
+			return alchemy.Block{}, fmt.Errorf("failed to parse base fee: %w", err)
+		}
+		baseFee = float64(fee.Int64()) / 1e9
+	}
+
 	// Расчет статистики по gas
 	gasStats := CalculateGasStatsFromJSON(jsonBlock.Transactions)
 
@@ -120,15 +96,79 @@ func NewBlockMetricsFromJSON(jsonBlock alchemy.JSONBlock) (alchemy.Block, error)
 		BlockTime:         t,
 		BlockTimestamp:    timestamp,
 		TransactionsCount: len(jsonBlock.Transactions),
+		UnknownTxCount:    CountUnknownTxTypes(jsonBlock.Transactions),
 		BlockSizeBytes:    blockSize,
 		GasLimit:          gasLimit,
 		GasUsed:           gasUsed,
 		BlockFullness:     float64(gasUsed) / float64(gasLimit) * 100,
+		BaseFee:           baseFee,
 		Validator:         jsonBlock.Miner,
 		GasStats:          gasStats,
+		Transactions:      NewTransactionsFromJSON(jsonBlock.Transactions, baseFee),
 	}, nil
 }
 
+// NewTransactionsFromJSON разбирает газовые поля транзакций, baseFee в gwei.
+// Транзакции с невалидной ценой пропускаются, как и в CalculateGasStatsFromJSON.
+func NewTransactionsFromJSON(transactions []alchemy.JSONTransaction, baseFee float64) []alchemy.Transaction {
+	result := make([]alchemy.Transaction, 0, len(transactions))
+	for i, tx := range transactions {
+		price, err := hexToGwei(tx.GasPrice)
+		if err != nil {
+			continue
+		}
+
+		var txType uint64
+		if tx.Type != "" {
+			if txType, err = hexutil.DecodeUint64(tx.Type); err != nil {
+				continue
+			}
+		}
+
+		// В блоке gasPrice у EIP-1559 транзакций уже фактическая цена: base fee + tip
+		tip := price - baseFee
+		if tip < 0 {
+			tip = 0
+		}
+
+		t := alchemy.Transaction{
+			TxIndex:  i,
+			Hash:     tx.Hash,
+			From:     tx.From,
+			To:       tx.To,
+			Type:     int(txType),
+			GasPrice: price,
+			Tip:      tip,
+		}
+		if maxFee, err := hexToGwei(tx.MaxFeePerGas); err == nil {
+			t.MaxFeePerGas = &maxFee
+		}
+		if maxTip, err := hexToGwei(tx.MaxPriorityFeePerGas); err == nil {
+			t.MaxPriorityFeePerGas = &maxTip
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func hexToGwei(s string) (float64, error) {
+	value, err := hexutil.DecodeBig(s)
+	if err != nil {
+		return 0, err
+	}
+	return float64(value.Int64()) / 1e9, nil
+}
+
+func CountUnknownTxTypes(transactions []alchemy.JSONTransaction) int {
+	var unknown int
+	for _, tx := range transactions {
+		if !knownTxTypes[tx.Type] {
+			unknown++
+		}
+	}
+	return unknown
+}
+
 func CalculateGasStatsFromJSON(transactions []alchemy.JSONTransaction) alchemy.GasStats {
 	const weiToGwei = 1e9 // 1 gwei = 10^9 wei
 
@@ -2,12 +2,12 @@ package collect
 
 import (
 	"blocks_gas_validators/internal/configs"
+	minerMetrics "blocks_gas_validators/internal/metrics"
 	"blocks_gas_validators/internal/miner/alchemy"
 	alchemyClient "blocks_gas_validators/pkg/client/alchemy"
 	"blocks_gas_validators/pkg/logging"
 	"context"
 	"fmt"
-	"math/big"
 	"sync"
 	"time"
 
@@ -20,39 +20,66 @@ type blockCollector struct {
 	client  *alchemyClient.Client
 	limiter *rate.Limiter
 	logger  *logging.Logger
+	// archive - необязательный архив сырых ответов, nil если выключен
+	archive       alchemy.Archive
+	headListeners []alchemy.HeadListener
 }
 
-func NewBlockCollector(client *alchemyClient.Client, logger *logging.Logger, limit int) alchemy.Collector {
+func NewBlockCollector(client *alchemyClient.Client, archive alchemy.Archive, logger *logging.Logger, limit int, headListeners ...alchemy.HeadListener) alchemy.Collector {
 	return &blockCollector{
-		client:  client,
-		limiter: rate.NewLimiter(rate.Limit(limit), 10),
-		logger:  logger,
+		client:        client,
+		limiter:       rate.NewLimiter(rate.Limit(limit), 10),
+		logger:        logger,
+		archive:       archive,
+		headListeners: headListeners,
 	}
 }
 
-func (bc *blockCollector) CollectBlockByNumber(ctx context.Context, blockNumber uint64) (*alchemy.Block, error) {
-	block, err := bc.client.Client.BlockByNumber(ctx, big.NewInt(int64(blockNumber)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch block %d: %w", blockNumber, err)
+// call - RPC вызов с учётом в метриках
+func (bc *blockCollector) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	started := time.Now()
+	err := bc.client.Client.Client().CallContext(ctx, result, method, args...)
+	minerMetrics.ObserveRPC(bc.client.NetworkName, method, started, err)
+	return err
+}
 
-	}
-	metrics := NewBlockMetrics(block)
-	return &metrics, nil
+func (bc *blockCollector) wait(ctx context.Context) error {
+	started := time.Now()
+	err := bc.limiter.Wait(ctx)
+	minerMetrics.LimiterWait.WithLabelValues(bc.client.NetworkName).Observe(time.Since(started).Seconds())
+	return err
 }
 
-func (bc *blockCollector) CollectBlockByNumberJSON(ctx context.Context, blockNumber uint64) (*alchemy.Block, error) {
-	var jsonBlock alchemy.JSONBlock
-	err := bc.client.Client.Client().Call(&jsonBlock, "eth_getBlockByNumber", hexutil.EncodeUint64(blockNumber), true)
+// CollectBlockByNumber забирает блок сырым JSON, а не через ethclient.BlockByNumber:
+// go-ethereum не декодирует неизвестные ему типы транзакций (deposit 0x7e в OP-stack и т.п.)
+// и из-за одной такой транзакции терялся бы весь блок.
+func (bc *blockCollector) CollectBlockByNumber(ctx context.Context, blockNumber uint64) (*alchemy.Block, error) {
+	raw := &alchemy.RawBlock{Number: blockNumber}
+	err := bc.call(ctx, &raw.Block, "eth_getBlockByNumber", hexutil.EncodeUint64(blockNumber), true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch block %d: %w", blockNumber, err)
 	}
 
-	metrics, err := NewBlockMetricsFromJSON(jsonBlock)
+	if isOPStack(bc.client.NetworkName) {
+		if raw.Receipts, err = bc.collectReceipts(ctx, blockNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	metrics, err := NewBlockMetricsFromRaw(raw)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process block %d: %w", blockNumber, err)
 	}
 
-	return &metrics, nil
+	// Архив вспомогательный: его ошибка не должна терять уже полученный блок
+	if bc.archive != nil {
+		if err := bc.archive.Put(bc.client.NetworkName, raw); err != nil {
+			bc.logger.Warnf("failed to archive block %d: %v", blockNumber, err)
+		}
+	}
+
+	minerMetrics.BlocksFetched.WithLabelValues(bc.client.NetworkName).Inc()
+	return metrics, nil
 }
 
 func (bc *blockCollector) SubscribeNewBlocks(ctx context.Context, maxRetries int) (<-chan *alchemy.Block, error) {
@@ -80,7 +107,12 @@ func (bc *blockCollector) SubscribeNewBlocks(ctx context.Context, maxRetries int
 				return
 
 			case header := <-headers:
-				if err := bc.limiter.Wait(ctx); err != nil {
+				minerMetrics.ObserveHead(bc.client.NetworkName, header.Number.Uint64())
+				for _, l := range bc.headListeners {
+					l.OnHead(header.Number.Uint64())
+				}
+
+				if err := bc.wait(ctx); err != nil {
 					bc.logger.Errorf("rate limiter wait failed: %v", err)
 					continue
 				}
@@ -103,6 +135,7 @@ func (bc *blockCollector) SubscribeNewBlocks(ctx context.Context, maxRetries int
 
 				if blockErr != nil {
 					bc.logger.Errorf("block %d failed after %d attempts: %v", header.Number.Uint64(), maxRetries, blockErr)
+					minerMetrics.BlocksFailed.WithLabelValues(bc.client.NetworkName, "fetch").Inc()
 					continue
 				}
 
@@ -148,14 +181,15 @@ func (bc *blockCollector) CollectHistoryBlocksBatch(ctx context.Context, cfg con
 						return
 					}
 
-					if err := bc.limiter.Wait(ctx); err != nil {
+					if err := bc.wait(ctx); err != nil {
 						bc.logger.Errorf("rate limiter error: %v", err)
 						return
 					}
 
-					block, err := bc.CollectBlockByNumberJSON(ctx, num)
+					block, err := bc.CollectBlockByNumber(ctx, num)
 					if err != nil {
 						bc.logger.Warnf("worker %d failed to fetch block %d: %v", workerID, num, err)
+						minerMetrics.BlocksFailed.WithLabelValues(bc.client.NetworkName, "fetch").Inc()
 						continue
 					}
 					results <- block
@@ -169,8 +203,12 @@ func (bc *blockCollector) CollectHistoryBlocksBatch(ctx context.Context, cfg con
 		close(results)
 	}()
 
+	done := make(chan struct{})
+	go bc.sampleChannelDepth(ctx, done, blockNumbers, results, out)
+
 	go func() {
 		defer close(out)
+		defer close(done)
 
 		var batch []*alchemy.Block
 		for {
@@ -200,3 +238,23 @@ func (bc *blockCollector) CollectHistoryBlocksBatch(ctx context.Context, cfg con
 
 	return out
 }
+
+// sampleChannelDepth раз в секунду снимает заполненность каналов history пайплайна
+func (bc *blockCollector) sampleChannelDepth(ctx context.Context, done <-chan struct{}, blockNumbers chan uint64, results chan *alchemy.Block, out chan []*alchemy.Block) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	chain := bc.client.NetworkName
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-ticker.C:
+			minerMetrics.ChannelDepth.WithLabelValues(chain, "block_numbers").Set(float64(len(blockNumbers)))
+			minerMetrics.ChannelDepth.WithLabelValues(chain, "results").Set(float64(len(results)))
+			minerMetrics.ChannelDepth.WithLabelValues(chain, "batches").Set(float64(len(out)))
+		}
+	}
+}
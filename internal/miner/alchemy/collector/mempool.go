@@ -0,0 +1,210 @@
+package collect
+
+import (
+	minerMetrics "blocks_gas_validators/internal/metrics"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// Догрузка по хэшу идёт вне цикла чтения подписки: иначе при потоке анонсов выше
+	// лимита RPC переполняется буфер подписки и нода её закрывает
+	pendingLookupWorkers = 8
+	pendingLookupQueue   = 10000
+)
+
+func (bc *blockCollector) SubscribePendingTransactions(ctx context.Context) (<-chan *alchemy.PendingTx, error) {
+	out := make(chan *alchemy.PendingTx, 1000)
+
+	// С флагом true нода присылает полные объекты транзакций. Часть нод флаг игнорирует
+	// и шлёт хэши, часть отклоняет подписку с ним - тогда подписываемся на одни хэши
+	raw := make(chan json.RawMessage, 1000)
+	sub, err := bc.client.Client.Client().EthSubscribe(ctx, raw, "newPendingTransactions", true)
+	if err != nil {
+		bc.logger.Warnf("node of chain %s rejected full pending transactions (%v), falling back to hashes with eth_getTransactionByHash lookups", bc.client.NetworkName, err)
+		sub, err = bc.client.Client.Client().EthSubscribe(ctx, raw, "newPendingTransactions")
+		if err != nil {
+			return nil, fmt.Errorf("subscribe pending transactions error: %w", err)
+		}
+	}
+
+	bc.logger.Infof("subscribed to pending transactions on chain: %s", bc.client.NetworkName)
+
+	hashes := make(chan string, pendingLookupQueue)
+	var wg sync.WaitGroup
+	for i := 0; i < pendingLookupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hash := range hashes {
+				tx, err := bc.lookupPendingTx(ctx, hash)
+				if err != nil {
+					bc.logger.Debugf("skip pending transaction: %v", err)
+					continue
+				}
+				if tx != nil {
+					bc.emitPendingTx(out, tx)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer func() {
+			close(hashes)
+			wg.Wait()
+			close(out)
+		}()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				bc.logger.Infof("context cancelled for pending transactions on chain: %s", bc.client.NetworkName)
+				return
+
+			case err := <-sub.Err():
+				bc.logger.Errorf("pending transactions subscription error: %v", err)
+				return
+
+			case msg := <-raw:
+				var hash string
+				if err := json.Unmarshal(msg, &hash); err == nil {
+					// Пришёл только хэш, транзакцию догружают воркеры
+					select {
+					case hashes <- hash:
+					default:
+						minerMetrics.MempoolDropped.WithLabelValues(bc.client.NetworkName, "lookup_queue").Inc()
+					}
+					continue
+				}
+
+				var jsonTx alchemy.JSONTransaction
+				if err := json.Unmarshal(msg, &jsonTx); err != nil {
+					bc.logger.Debugf("skip pending transaction: failed to decode: %v", err)
+					continue
+				}
+				tx, err := NewPendingTxFromJSON(jsonTx, time.Now())
+				if err != nil {
+					bc.logger.Debugf("skip pending transaction: %v", err)
+					continue
+				}
+				bc.emitPendingTx(out, tx)
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (bc *blockCollector) emitPendingTx(out chan<- *alchemy.PendingTx, tx *alchemy.PendingTx) {
+	select {
+	case out <- tx:
+	default:
+		minerMetrics.MempoolDropped.WithLabelValues(bc.client.NetworkName, "out").Inc()
+		bc.logger.Debugf("pending transactions channel full, dropping tx %s", tx.Hash)
+	}
+}
+
+func (bc *blockCollector) lookupPendingTx(ctx context.Context, hash string) (*alchemy.PendingTx, error) {
+	if err := bc.wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+	var found *alchemy.JSONTransaction
+	if err := bc.call(ctx, &found, "eth_getTransactionByHash", hash); err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", hash, err)
+	}
+	// Уже вытеснена из мемпула или включена в блок
+	if found == nil || found.BlockNumber != "" {
+		return nil, nil
+	}
+	return NewPendingTxFromJSON(*found, time.Now())
+}
+
+// SubscribeIncludedTransactions по каждому новому head отдаёт хэши транзакций блока,
+// чтобы окно мемпула не держало уже включённые транзакции до истечения window
+func (bc *blockCollector) SubscribeIncludedTransactions(ctx context.Context) (<-chan []string, error) {
+	out := make(chan []string, 100)
+
+	headers := make(chan *types.Header)
+	sub, err := bc.client.Client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe error: %w", err)
+	}
+
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case err := <-sub.Err():
+				bc.logger.Errorf("included transactions subscription error: %v", err)
+				return
+
+			case header := <-headers:
+				if err := bc.wait(ctx); err != nil {
+					continue
+				}
+				// Без полных транзакций блок приходит со списком хэшей
+				var block struct {
+					Transactions []string `json:"transactions"`
+				}
+				if err := bc.call(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(header.Number), false); err != nil {
+					bc.logger.Warnf("failed to fetch transactions of block %d: %v", header.Number.Uint64(), err)
+					continue
+				}
+
+				select {
+				case out <- block.Transactions:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func NewPendingTxFromJSON(jsonTx alchemy.JSONTransaction, seenAt time.Time) (*alchemy.PendingTx, error) {
+	const weiToGwei = 1e9 // 1 gwei = 10^9 wei
+
+	// Для EIP-1559 транзакций ставка пользователя - maxFeePerGas
+	priceHex := jsonTx.MaxFeePerGas
+	if priceHex == "" {
+		priceHex = jsonTx.GasPrice
+	}
+
+	price, err := hexutil.DecodeBig(priceHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas price of %s: %w", jsonTx.Hash, err)
+	}
+
+	tx := &alchemy.PendingTx{
+		Hash:     jsonTx.Hash,
+		Type:     jsonTx.Type,
+		GasPrice: float64(price.Int64()) / weiToGwei,
+		SeenAt:   seenAt,
+	}
+
+	// У legacy транзакций чаевые совпадают с gasPrice
+	tx.MaxPriorityFee = tx.GasPrice
+	if jsonTx.MaxPriorityFeePerGas != "" {
+		if tip, err := hexutil.DecodeBig(jsonTx.MaxPriorityFeePerGas); err == nil {
+			tx.MaxPriorityFee = float64(tip.Int64()) / weiToGwei
+		}
+	}
+
+	return tx, nil
+}
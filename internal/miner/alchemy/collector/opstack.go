@@ -0,0 +1,77 @@
+package collect
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/chains"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// depositTxType - тип deposit-транзакций OP-stack (L1 -> L2), у них нет L1 data fee
+const depositTxType = "0x7e"
+
+func isOPStack(network string) bool {
+	return chains.AlchemyChains[network].OPStack
+}
+
+// collectReceipts возвращает квитанции блока как есть, L1 комиссии считаются из них в NewBlockMetricsFromRaw
+func (bc *blockCollector) collectReceipts(ctx context.Context, blockNumber uint64) (json.RawMessage, error) {
+	// Receipts - отдельный RPC вызов, поэтому тоже проходит через лимитер
+	if err := bc.wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+
+	var receipts json.RawMessage
+	err := bc.call(ctx, &receipts, "eth_getBlockReceipts", hexutil.EncodeUint64(blockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch receipts for block %d: %w", blockNumber, err)
+	}
+	return receipts, nil
+}
+
+func CalculateL1FeeStats(receipts []alchemy.JSONReceipt) alchemy.L1FeeStats {
+	const weiToGwei = 1e9 // 1 gwei = 10^9 wei
+
+	var stats alchemy.L1FeeStats
+	var feeTxs int
+
+	for _, r := range receipts {
+		if r.Type == depositTxType {
+			stats.DepositCount++
+			continue
+		}
+
+		if r.L1Fee != "" {
+			fee, err := hexutil.DecodeBig(r.L1Fee)
+			if err == nil {
+				stats.Total += float64(fee.Int64()) / weiToGwei
+				feeTxs++
+			}
+		}
+
+		if gasUsed, err := hexutil.DecodeUint64(r.L1GasUsed); err == nil {
+			stats.GasUsed += gasUsed
+		}
+
+		// Скаляры одинаковы для всех транзакций блока, берём первые валидные
+		if stats.BaseFeeScalar == 0 {
+			if v, err := hexutil.DecodeUint64(r.L1BaseFeeScalar); err == nil {
+				stats.BaseFeeScalar = v
+			}
+		}
+		if stats.BlobBaseFeeScalar == 0 {
+			if v, err := hexutil.DecodeUint64(r.L1BlobBaseFeeScalar); err == nil {
+				stats.BlobBaseFeeScalar = v
+			}
+		}
+	}
+
+	if feeTxs > 0 {
+		stats.Avg = stats.Total / float64(feeTxs)
+	}
+
+	return stats
+}
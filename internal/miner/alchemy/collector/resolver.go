@@ -0,0 +1,349 @@
+package collect
+
+import (
+	"blocks_gas_validators/internal/configs"
+	minerMetrics "blocks_gas_validators/internal/metrics"
+	alchemyClient "blocks_gas_validators/pkg/client/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/time/rate"
+)
+
+// cacheConfirmations - точки ближе к head не сохраняются в файл: их ещё может переписать реорг
+const cacheConfirmations = 128
+
+// blockTimes - всё, что резолверу нужно от ноды: номер head и timestamp блока
+type blockTimes interface {
+	HeadNumber(ctx context.Context) (uint64, error)
+	BlockTimestamp(ctx context.Context, number uint64) (uint64, error)
+}
+
+// rpcBlockTimes читает timestamp только из заголовка: полные блоки не нужны,
+// а ethclient не декодирует часть типов транзакций
+type rpcBlockTimes struct {
+	client *alchemyClient.Client
+}
+
+func (c rpcBlockTimes) HeadNumber(ctx context.Context) (uint64, error) {
+	started := time.Now()
+	number, err := c.client.Client.BlockNumber(ctx)
+	minerMetrics.ObserveRPC(c.client.NetworkName, "eth_blockNumber", started, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get head: %w", err)
+	}
+	return number, nil
+}
+
+func (c rpcBlockTimes) BlockTimestamp(ctx context.Context, number uint64) (uint64, error) {
+	var header struct {
+		Timestamp string `json:"timestamp"`
+	}
+	started := time.Now()
+	err := c.client.Client.Client().CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	minerMetrics.ObserveRPC(c.client.NetworkName, "eth_getBlockByNumber", started, err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch block %d header: %w", number, err)
+	}
+	if header.Timestamp == "" {
+		return 0, fmt.Errorf("block %d not found", number)
+	}
+	ts, err := hexutil.DecodeUint64(header.Timestamp)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse block %d timestamp: %w", number, err)
+	}
+	return ts, nil
+}
+
+type blockPoint struct {
+	Number    uint64 `json:"number"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// BlockResolver переводит время в номера блоков бинарным поиском по timestamp.
+// Все запрошенные точки кэшируются и сужают следующие поиски, поэтому повторное
+// разрешение соседних дат стоит несколько запросов вместо ~25.
+type BlockResolver struct {
+	rpc       blockTimes
+	chain     string
+	limiter   *rate.Limiter
+	logger    *logging.Logger
+	cachePath string
+
+	// points отсортированы по номеру
+	points []blockPoint
+	head   blockPoint
+}
+
+func NewBlockResolver(client *alchemyClient.Client, limit int, cacheDir string, logger *logging.Logger) *BlockResolver {
+	return newBlockResolver(rpcBlockTimes{client: client}, client.NetworkName, limit, cacheDir, logger)
+}
+
+func newBlockResolver(rpc blockTimes, chain string, limit int, cacheDir string, logger *logging.Logger) *BlockResolver {
+	r := &BlockResolver{
+		rpc:     rpc,
+		chain:   chain,
+		limiter: rate.NewLimiter(rate.Limit(limit), 10),
+		logger:  logger,
+	}
+	if cacheDir != "" {
+		r.cachePath = filepath.Join(cacheDir, fmt.Sprintf("%s_block_times.json", chain))
+		r.load()
+	}
+	return r
+}
+
+// ResolveRange заменяет start/end в cfg, если диапазон задан временем или относительно head.
+// Без таких настроек ничего не делает и в RPC не ходит.
+func (r *BlockResolver) ResolveRange(ctx context.Context, cfg *configs.AlchemyConfig) error {
+	if cfg.LastBlocks == 0 && cfg.Last == 0 && cfg.StartTime == "" && cfg.EndTime == "" {
+		return nil
+	}
+	defer r.save()
+
+	head, err := r.currentHead(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cfg.LastBlocks > 0:
+		cfg.End = head.Number
+		cfg.Start = 0
+		if head.Number+1 > cfg.LastBlocks {
+			cfg.Start = head.Number + 1 - cfg.LastBlocks
+		}
+
+	case cfg.Last > 0:
+		start, err := r.FirstAtOrAfter(ctx, time.Now().Add(-cfg.Last))
+		if err != nil {
+			return err
+		}
+		cfg.Start, cfg.End = start, head.Number
+
+	default:
+		if cfg.StartTime != "" {
+			t, err := ParseTime(cfg.StartTime)
+			if err != nil {
+				return fmt.Errorf("invalid start_time: %w", err)
+			}
+			if cfg.Start, err = r.FirstAtOrAfter(ctx, t); err != nil {
+				return err
+			}
+		}
+		if cfg.EndTime != "" {
+			t, err := ParseTime(cfg.EndTime)
+			if err != nil {
+				return fmt.Errorf("invalid end_time: %w", err)
+			}
+			// end_time не включается: последний блок - предыдущий перед первым в end_time
+			first, err := r.FirstAtOrAfter(ctx, t)
+			if err != nil {
+				return err
+			}
+			if first == 0 {
+				return fmt.Errorf("end_time %s is before the first block", cfg.EndTime)
+			}
+			cfg.End = first - 1
+		}
+	}
+
+	if cfg.End < cfg.Start {
+		return fmt.Errorf("resolved range is empty: start %d, end %d", cfg.Start, cfg.End)
+	}
+	r.logger.Infof("resolved %s block range: start %d, end %d (head %d)", r.chain, cfg.Start, cfg.End, head.Number)
+	return nil
+}
+
+// currentHead - head сети с его timestamp, запрашивается один раз на резолвер
+func (r *BlockResolver) currentHead(ctx context.Context) (blockPoint, error) {
+	if r.head.Number != 0 {
+		return r.head, nil
+	}
+
+	if err := r.limiter.Wait(ctx); err != nil {
+		return blockPoint{}, err
+	}
+	number, err := r.rpc.HeadNumber(ctx)
+	if err != nil {
+		return blockPoint{}, err
+	}
+
+	head, err := r.point(ctx, number)
+	if err != nil {
+		return blockPoint{}, err
+	}
+	r.head = head
+	return head, nil
+}
+
+// FirstAtOrAfter возвращает первый блок с timestamp >= t. Если t позже head - head+1.
+func (r *BlockResolver) FirstAtOrAfter(ctx context.Context, t time.Time) (uint64, error) {
+	head, err := r.currentHead(ctx)
+	if err != nil {
+		return 0, err
+	}
+	target := uint64(max(t.Unix(), 0))
+	if head.Timestamp < target {
+		return head.Number + 1, nil
+	}
+
+	// lo - последняя известная точка раньше target, hi - первая не раньше
+	lo, hi, ok := r.bracket(target)
+	if !ok {
+		genesis, err := r.point(ctx, 0)
+		if err != nil {
+			return 0, err
+		}
+		if genesis.Timestamp >= target {
+			return 0, nil
+		}
+		lo, hi, _ = r.bracket(target)
+	}
+
+	// Интерполяция по времени быстро подходит к ответу при ровном block time,
+	// чередование с делением пополам оставляет гарантию O(log n) запросов
+	for step := 0; hi.Number-lo.Number > 1; step++ {
+		mid := lo.Number + (hi.Number-lo.Number)/2
+		if step%2 == 0 && hi.Timestamp > lo.Timestamp {
+			guess := lo.Number + uint64(float64(hi.Number-lo.Number)*float64(target-lo.Timestamp)/float64(hi.Timestamp-lo.Timestamp))
+			mid = min(max(guess, lo.Number+1), hi.Number-1)
+		}
+
+		p, err := r.point(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+		if p.Timestamp < target {
+			lo = p
+		} else {
+			hi = p
+		}
+	}
+	return hi.Number, nil
+}
+
+// bracket ищет в кэше соседние точки вокруг target
+func (r *BlockResolver) bracket(target uint64) (blockPoint, blockPoint, bool) {
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].Timestamp >= target })
+	if i == 0 || i == len(r.points) {
+		return blockPoint{}, blockPoint{}, false
+	}
+	return r.points[i-1], r.points[i], true
+}
+
+func (r *BlockResolver) point(ctx context.Context, number uint64) (blockPoint, error) {
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].Number >= number })
+	if i < len(r.points) && r.points[i].Number == number {
+		return r.points[i], nil
+	}
+
+	if err := r.limiter.Wait(ctx); err != nil {
+		return blockPoint{}, err
+	}
+
+	ts, err := r.rpc.BlockTimestamp(ctx, number)
+	if err != nil {
+		return blockPoint{}, err
+	}
+
+	p := blockPoint{Number: number, Timestamp: ts}
+	r.points = append(r.points, blockPoint{})
+	copy(r.points[i+1:], r.points[i:])
+	r.points[i] = p
+	return p, nil
+}
+
+func (r *BlockResolver) load() {
+	data, err := os.ReadFile(r.cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		r.logger.Warnf("failed to read block time cache: %v", err)
+		return
+	}
+
+	var points []blockPoint
+	if err := json.Unmarshal(data, &points); err != nil {
+		r.logger.Warnf("ignore corrupted block time cache %s: %v", r.cachePath, err)
+		return
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Number < points[j].Number })
+	r.points = points
+}
+
+func (r *BlockResolver) save() {
+	if r.cachePath == "" {
+		return
+	}
+
+	points := make([]blockPoint, 0, len(r.points))
+	for _, p := range r.points {
+		if r.head.Number == 0 || p.Number+cacheConfirmations <= r.head.Number {
+			points = append(points, p)
+		}
+	}
+
+	data, err := json.Marshal(points)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(r.cachePath), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(r.cachePath, data, 0o644)
+	}
+	if err != nil {
+		r.logger.Warnf("failed to save block time cache: %v", err)
+	}
+}
+
+// TimeRange переводит start_time/end_time и last из cfg в интервал [from, to) без RPC -
+// для режимов, которые фильтруют уже сохранённые блоки по block_time.
+// ok false, если диапазон задан только номерами.
+func TimeRange(cfg configs.AlchemyConfig, now time.Time) (from, to time.Time, ok bool, err error) {
+	if cfg.Last == 0 && cfg.StartTime == "" && cfg.EndTime == "" {
+		return time.Time{}, time.Time{}, false, nil
+	}
+
+	from, to = time.Unix(0, 0), now
+	if cfg.Last > 0 {
+		from = now.Add(-cfg.Last)
+	} else {
+		if cfg.StartTime != "" {
+			if from, err = ParseTime(cfg.StartTime); err != nil {
+				return time.Time{}, time.Time{}, false, fmt.Errorf("invalid start_time: %w", err)
+			}
+		}
+		if cfg.EndTime != "" {
+			if to, err = ParseTime(cfg.EndTime); err != nil {
+				return time.Time{}, time.Time{}, false, fmt.Errorf("invalid end_time: %w", err)
+			}
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("time range is empty: start %s, end %s", from, to)
+	}
+	return from, to, true, nil
+}
+
+// ParseTime разбирает RFC3339, дату 2006-01-02, "2006-01-02 15:04" (UTC) или unix секунды
+func ParseTime(v string) (time.Time, error) {
+	if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time %q, want RFC3339, 2006-01-02 or unix seconds", v)
+}
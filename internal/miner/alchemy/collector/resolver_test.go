@@ -0,0 +1,298 @@
+package collect
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logging.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return &logging.Logger{Entry: logrus.NewEntry(l)}
+}
+
+// fakeChain - timestamps блоков по номеру, head - последний
+type fakeChain struct {
+	times []uint64
+	calls int
+}
+
+func (c *fakeChain) HeadNumber(_ context.Context) (uint64, error) {
+	return uint64(len(c.times) - 1), nil
+}
+
+func (c *fakeChain) BlockTimestamp(_ context.Context, number uint64) (uint64, error) {
+	c.calls++
+	if number >= uint64(len(c.times)) {
+		return 0, fmt.Errorf("block %d not found", number)
+	}
+	return c.times[number], nil
+}
+
+// firstAtOrAfter - эталон линейным проходом
+func (c *fakeChain) firstAtOrAfter(target uint64) uint64 {
+	for n, ts := range c.times {
+		if ts >= target {
+			return uint64(n)
+		}
+	}
+	return uint64(len(c.times))
+}
+
+// regularChain - блок каждые step секунд начиная с genesis
+func regularChain(blocks int, genesis, step uint64) *fakeChain {
+	c := &fakeChain{times: make([]uint64, blocks)}
+	for i := range c.times {
+		c.times[i] = genesis + uint64(i)*step
+	}
+	return c
+}
+
+// burstyChain - несколько блоков в одну секунду (bnb) и редкие длинные паузы
+func burstyChain(blocks int) *fakeChain {
+	c := &fakeChain{times: make([]uint64, blocks)}
+	ts := uint64(1_600_000_000)
+	for i := range c.times {
+		switch {
+		case i%97 == 0:
+			ts += 600
+		case i%3 == 0:
+			ts++
+		}
+		c.times[i] = ts
+	}
+	return c
+}
+
+func newTestResolver(chain *fakeChain, cacheDir string) *BlockResolver {
+	return newBlockResolver(chain, "ethereum", 1_000_000, cacheDir, testLogger())
+}
+
+func TestFirstAtOrAfter(t *testing.T) {
+	regular := regularChain(10_000, 1_000_000, 12)
+	bursty := burstyChain(10_000)
+
+	cases := []struct {
+		name   string
+		chain  *fakeChain
+		target uint64
+		want   uint64
+	}{
+		{name: "before genesis", chain: regular, target: 10, want: 0},
+		{name: "at genesis", chain: regular, target: 1_000_000, want: 0},
+		{name: "exact block", chain: regular, target: 1_000_000 + 12*4321, want: 4321},
+		{name: "between blocks", chain: regular, target: 1_000_000 + 12*4321 + 5, want: 4322},
+		{name: "at head", chain: regular, target: 1_000_000 + 12*9999, want: 9999},
+		{name: "after head", chain: regular, target: 1_000_000 + 12*9999 + 1, want: 10_000},
+		{name: "repeated timestamps", chain: bursty, target: bursty.times[5000], want: bursty.firstAtOrAfter(bursty.times[5000])},
+		{name: "inside a pause", chain: bursty, target: bursty.times[97*40] - 300, want: 97 * 40},
+		{name: "repeated head timestamp", chain: bursty, target: bursty.times[9999], want: bursty.firstAtOrAfter(bursty.times[9999])},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestResolver(tc.chain, "")
+			got, err := r.FirstAtOrAfter(context.Background(), time.Unix(int64(tc.target), 0))
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("first block at or after %d = %d, want %d", tc.target, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFirstAtOrAfterMatchesLinearScan(t *testing.T) {
+	chain := burstyChain(5_000)
+	// Один резолвер на все цели: кэш точек от прошлых поисков не должен менять ответы
+	r := newTestResolver(chain, "")
+
+	first, last := chain.times[0], chain.times[len(chain.times)-1]
+	for target := first - 5; target <= last+5; target += 7 {
+		got, err := r.FirstAtOrAfter(context.Background(), time.Unix(int64(target), 0))
+		if err != nil {
+			t.Fatalf("resolve %d: %v", target, err)
+		}
+		if want := chain.firstAtOrAfter(target); got != want {
+			t.Fatalf("first block at or after %d = %d, want %d", target, got, want)
+		}
+	}
+}
+
+func TestFirstAtOrAfterRequestCount(t *testing.T) {
+	chain := regularChain(2_000_000, 1_438_269_973, 12)
+	r := newTestResolver(chain, "")
+
+	target := chain.times[1_234_567] + 3
+	got, err := r.FirstAtOrAfter(context.Background(), time.Unix(int64(target), 0))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got != 1_234_568 {
+		t.Fatalf("got %d, want 1234568", got)
+	}
+	// Ровный block time: интерполяция попадает почти сразу, деление пополам дало бы ~25
+	if chain.calls > 8 {
+		t.Fatalf("%d header requests for a regular chain", chain.calls)
+	}
+}
+
+func TestFirstAtOrAfterUsesCache(t *testing.T) {
+	chain := regularChain(10_000, 1_000_000, 12)
+	dir := t.TempDir()
+
+	// Соседние точки уже в кэше: поиск не должен запрашивать ничего, кроме head
+	points := []blockPoint{{Number: 4000, Timestamp: chain.times[4000]}, {Number: 4001, Timestamp: chain.times[4001]}}
+	data, err := json.Marshal(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ethereum_block_times.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newTestResolver(chain, dir)
+	got, err := r.FirstAtOrAfter(context.Background(), time.Unix(int64(chain.times[4000]+1), 0))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got != 4001 {
+		t.Fatalf("got %d, want 4001", got)
+	}
+	if chain.calls != 1 {
+		t.Fatalf("%d header requests, want only the head", chain.calls)
+	}
+}
+
+func TestResolveRange(t *testing.T) {
+	chain := regularChain(10_000, 1_000_000, 12)
+	at := func(n int) string { return fmt.Sprint(chain.times[n]) }
+
+	cases := []struct {
+		name       string
+		cfg        configs.AlchemyConfig
+		start, end uint64
+		wantErr    bool
+	}{
+		{name: "no time options", cfg: configs.AlchemyConfig{Start: 5, End: 7}, start: 5, end: 7},
+		{name: "end time is exclusive", cfg: configs.AlchemyConfig{StartTime: at(100), EndTime: at(200)}, start: 100, end: 199},
+		{name: "end time between blocks", cfg: configs.AlchemyConfig{StartTime: at(100), EndTime: fmt.Sprint(chain.times[200] + 1)}, start: 100, end: 200},
+		{name: "only start time keeps end", cfg: configs.AlchemyConfig{StartTime: at(100), End: 150}, start: 100, end: 150},
+		{name: "last blocks", cfg: configs.AlchemyConfig{LastBlocks: 10}, start: 9990, end: 9999},
+		{name: "last blocks above head", cfg: configs.AlchemyConfig{LastBlocks: 20_000}, start: 0, end: 9999},
+		{name: "end time before first block", cfg: configs.AlchemyConfig{EndTime: at(0)}, wantErr: true},
+		{name: "empty range", cfg: configs.AlchemyConfig{StartTime: at(200), EndTime: at(200)}, wantErr: true},
+		{name: "bad time", cfg: configs.AlchemyConfig{StartTime: "yesterday"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			err := newTestResolver(chain, "").ResolveRange(context.Background(), &cfg)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got range %d-%d", cfg.Start, cfg.End)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if cfg.Start != tc.start || cfg.End != tc.end {
+				t.Fatalf("range %d-%d, want %d-%d", cfg.Start, cfg.End, tc.start, tc.end)
+			}
+		})
+	}
+}
+
+func TestCacheSkipsPointsNearHead(t *testing.T) {
+	chain := regularChain(1_000, 1_000_000, 12)
+	dir := t.TempDir()
+
+	r := newTestResolver(chain, dir)
+	cfg := configs.AlchemyConfig{StartTime: fmt.Sprint(chain.times[100]), EndTime: fmt.Sprint(chain.times[990])}
+	if err := r.ResolveRange(context.Background(), &cfg); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "ethereum_block_times.json"))
+	if err != nil {
+		t.Fatalf("read cache: %v", err)
+	}
+	var points []blockPoint
+	if err := json.Unmarshal(data, &points); err != nil {
+		t.Fatalf("decode cache: %v", err)
+	}
+	if len(points) == 0 {
+		t.Fatal("cache is empty")
+	}
+	for _, p := range points {
+		if p.Number+cacheConfirmations > 999 {
+			t.Fatalf("cached block %d is within %d blocks of head", p.Number, cacheConfirmations)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []string{"1709251200", "2024-03-01", "2024-03-01T00:00:00Z", "2024-03-01 00:00", "2024-03-01T00:00"} {
+		got, err := ParseTime(v)
+		if err != nil {
+			t.Fatalf("parse %q: %v", v, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("parse %q = %s, want %s", v, got, want)
+		}
+	}
+	if _, err := ParseTime("01.03.2024"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		cfg      configs.AlchemyConfig
+		from, to time.Time
+		byTime   bool
+		wantErr  bool
+	}{
+		{name: "block numbers only", cfg: configs.AlchemyConfig{Start: 5, End: 7}},
+		{name: "start and end", cfg: configs.AlchemyConfig{StartTime: "2024-03-01", EndTime: "2024-03-02"}, from: day, to: day.Add(24 * time.Hour), byTime: true},
+		{name: "only start runs to now", cfg: configs.AlchemyConfig{StartTime: "2024-03-01"}, from: day, to: now, byTime: true},
+		{name: "only end starts at epoch", cfg: configs.AlchemyConfig{EndTime: "2024-03-01"}, from: time.Unix(0, 0), to: day, byTime: true},
+		{name: "last", cfg: configs.AlchemyConfig{Last: 6 * time.Hour}, from: now.Add(-6 * time.Hour), to: now, byTime: true},
+		{name: "reversed", cfg: configs.AlchemyConfig{StartTime: "2024-03-02", EndTime: "2024-03-01"}, wantErr: true},
+		{name: "start in the future", cfg: configs.AlchemyConfig{StartTime: "2024-04-01"}, wantErr: true},
+		{name: "bad time", cfg: configs.AlchemyConfig{EndTime: "tomorrow"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, byTime, err := TimeRange(tc.cfg, now)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got [%s, %s)", from, to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("time range: %v", err)
+			}
+			if byTime != tc.byTime || !from.Equal(tc.from) || !to.Equal(tc.to) {
+				t.Fatalf("got [%s, %s) by time %t, want [%s, %s) by time %t", from, to, byTime, tc.from, tc.to, tc.byTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *repository) InsertMissedSlots(ctx context.Context, slots []*alchemy.MissedSlot, chain string) error {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	// Один и тот же пропуск может быть найден и live, и history режимом
+	q := `
+		INSERT INTO missed_slots (
+			chain, slot, slot_time,
+			prev_block_number, prev_proposer,
+			next_block_number, next_proposer
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (chain, slot) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, s := range slots {
+		batch.Queue(q, chain, s.Slot, s.SlotTime, s.PrevBlockNumber, s.PrevProposer, s.NextBlockNumber, s.NextProposer)
+	}
+
+	br := r.client.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for range slots {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("insert missed slots: %w", err)
+		}
+	}
+
+	r.logger.Infof("Recorded %d missed slots for chain %s", len(slots), chain)
+	return nil
+}
@@ -0,0 +1,415 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/pkg/client/postgresql"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+
+	RetentionDetach  = "detach"
+	RetentionDrop    = "drop"
+	RetentionArchive = "archive"
+)
+
+type partitionRange struct {
+	name       string
+	start, end time.Time
+}
+
+type partitionPolicy struct {
+	granularity string
+	retention   time.Duration
+}
+
+// PartitionManager создаёт партиции по времени заранее и по требованию, помнит уже
+// существующие, чтобы не ходить в базу на каждую вставку, и убирает старые по retention.
+// Существующие партиции читаются из каталога вместе с границами: при смене зоны или
+// гранулярности новые диапазоны подрезаются по соседям и не пересекаются со старыми.
+type PartitionManager struct {
+	client postgresql.Client
+	logger *logging.Logger
+	cfg    configs.PartitionsConfig
+	loc    *time.Location
+
+	mu    sync.Mutex
+	known map[string][]partitionRange
+	// touched - партиции, в которые в этом запуске писали, когда они уже были старше
+	// retention (backfill, import старых данных). Retention их не трогает до перезапуска.
+	touched map[string]bool
+	// createMu сериализует создание партиций, не блокируя попадания в кэш
+	createMu sync.Mutex
+}
+
+func NewPartitionManager(client postgresql.Client, cfg configs.PartitionsConfig, logger *logging.Logger) (*PartitionManager, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load partition timezone %s: %w", cfg.Timezone, err)
+	}
+
+	granularities := []string{cfg.Granularity}
+	for _, override := range cfg.Chains {
+		granularities = append(granularities, override.Granularity)
+	}
+	for _, g := range granularities {
+		switch g {
+		case "", GranularityDay, GranularityWeek, GranularityMonth:
+		default:
+			return nil, fmt.Errorf("unknown partition granularity: %s", g)
+		}
+	}
+	switch cfg.RetentionAction {
+	case RetentionDetach, RetentionDrop, RetentionArchive:
+	default:
+		return nil, fmt.Errorf("unknown partition retention action: %s", cfg.RetentionAction)
+	}
+
+	return &PartitionManager{
+		client:  client,
+		logger:  logger,
+		cfg:     cfg,
+		loc:     loc,
+		known:   make(map[string][]partitionRange),
+		touched: make(map[string]bool),
+	}, nil
+}
+
+// policy - настройки сети, которой принадлежит таблица <chain>_..., с откатом на общие
+func (m *PartitionManager) policy(table string) partitionPolicy {
+	p := partitionPolicy{granularity: m.cfg.Granularity, retention: m.cfg.Retention}
+	for chain, override := range m.cfg.Chains {
+		if !strings.HasPrefix(table, chain+"_") {
+			continue
+		}
+		if override.Granularity != "" {
+			p.granularity = override.Granularity
+		}
+		if override.Retention != 0 {
+			p.retention = override.Retention
+		}
+	}
+	return p
+}
+
+// bounds возвращает границы партиции гранулярности g, содержащей t
+func (m *PartitionManager) bounds(g string, t time.Time) (time.Time, time.Time) {
+	t = t.In(m.loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, m.loc)
+
+	switch g {
+	case GranularityWeek:
+		// Недели с понедельника, как date_trunc('week')
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, m.loc)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		return day, day.AddDate(0, 0, 1)
+	}
+}
+
+// Ensure гарантирует, что для t в table есть партиция. В обычном режиме ответ из кэша,
+// под mu только чтение и отметка кэша: запросы к базе идут под отдельным createMu.
+func (m *PartitionManager) Ensure(ctx context.Context, table string, t time.Time) error {
+	m.mu.Lock()
+	if i, ok := covering(m.known[table], t); ok {
+		m.touch(table, m.known[table][i])
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+
+	m.createMu.Lock()
+	defer m.createMu.Unlock()
+
+	// Каталог, а не кэш: пока ждали createMu, партицию мог создать другой писатель или майнер
+	ranges, err := m.load(ctx, table)
+	if err != nil {
+		return err
+	}
+	i, ok := covering(ranges, t)
+	if ok {
+		m.remember(table, ranges, &ranges[i])
+		return nil
+	}
+
+	start, end := m.bounds(m.policy(table).granularity, t)
+	// Подрезаем по соседям, созданным с другой зоной или гранулярностью
+	if i > 0 && ranges[i-1].end.After(start) {
+		start = ranges[i-1].end
+	}
+	if i < len(ranges) && ranges[i].start.Before(end) {
+		end = ranges[i].start
+	}
+
+	name, err := m.partitionName(ctx, table, start, ranges)
+	if err != nil {
+		return err
+	}
+	// IF NOT EXISTS - для второго майнера той же сети, создающего ту же партицию
+	sql := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s PARTITION OF %s
+		FOR VALUES FROM ('%s') TO ('%s')
+	`, name, table, start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+
+	m.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(sql)))
+	_, createErr := m.client.Exec(ctx, sql)
+
+	// IF NOT EXISTS молча пропускает и одноимённую отключённую (detach) таблицу, а ошибка
+	// пересечения бывает, когда партицию создали параллельно: итог проверяем по каталогу
+	if ranges, err = m.load(ctx, table); err != nil {
+		m.mu.Lock()
+		delete(m.known, table)
+		m.mu.Unlock()
+		return err
+	}
+	if i, ok := covering(ranges, t); ok {
+		if createErr == nil && ranges[i].name == name {
+			m.logger.Infof("created partition %s [%s, %s)", name, start, end)
+		}
+		m.remember(table, ranges, &ranges[i])
+		return nil
+	}
+	if createErr != nil {
+		return fmt.Errorf("create partition %s: %w", name, createErr)
+	}
+	return fmt.Errorf("partition %s for %s not attached to %s", name, t, table)
+}
+
+// remember кладёт в кэш прочитанные из каталога партиции table и отмечает использованную
+func (m *PartitionManager) remember(table string, ranges []partitionRange, used *partitionRange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.known[table] = ranges
+	if used != nil {
+		m.touch(table, *used)
+	}
+}
+
+// touch отмечает запись в партицию, которая уже старше retention. Вызывается под mu.
+func (m *PartitionManager) touch(table string, r partitionRange) {
+	if retention := m.policy(table).retention; retention > 0 && !r.end.After(time.Now().Add(-retention)) {
+		m.touched[r.name] = true
+	}
+}
+
+// covering ищет партицию, содержащую t; иначе возвращает позицию вставки
+func covering(ranges []partitionRange, t time.Time) (int, bool) {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].end.After(t) })
+	return i, i < len(ranges) && !ranges[i].start.After(t)
+}
+
+// partitionName подбирает свободное имя. Занятым считается и имя отключённой партиции,
+// которая осталась обычной таблицей в той же схеме.
+func (m *PartitionManager) partitionName(ctx context.Context, table string, start time.Time, existing []partitionRange) (string, error) {
+	local := start.In(m.loc)
+	base := fmt.Sprintf("%s_%s", table, local.Format("2006_01_02"))
+	// Подрезанная партиция начинается не с полуночи
+	if local.Hour() != 0 || local.Minute() != 0 || local.Second() != 0 {
+		base = fmt.Sprintf("%s_%s", table, local.Format("2006_01_02_150405"))
+	}
+
+	q := `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relname = $1 AND n.nspname = current_schema()
+		)
+	`
+	name := base
+	for attempt := 1; ; attempt++ {
+		taken := false
+		for _, r := range existing {
+			if r.name == name {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			if err := m.client.QueryRow(ctx, q, name).Scan(&taken); err != nil {
+				return "", fmt.Errorf("check partition name %s: %w", name, err)
+			}
+		}
+		if !taken {
+			return name, nil
+		}
+
+		name = fmt.Sprintf("%s_%d", base, start.Unix())
+		if attempt > 1 {
+			name = fmt.Sprintf("%s_%d_%d", base, start.Unix(), attempt)
+		}
+	}
+}
+
+var boundRe = regexp.MustCompile(`FROM \('([^']+)'\) TO \('([^']+)'\)`)
+
+// load читает из каталога подключённые партиции table и их границы. Кэш не меняет.
+func (m *PartitionManager) load(ctx context.Context, table string) ([]partitionRange, error) {
+	q := `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = $1
+	`
+
+	rows, err := m.client.Query(ctx, q, table)
+	if err != nil {
+		return nil, fmt.Errorf("list partitions of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var ranges []partitionRange
+	for rows.Next() {
+		var name, bound string
+		if err := rows.Scan(&name, &bound); err != nil {
+			return nil, fmt.Errorf("scan partition: %w", err)
+		}
+
+		match := boundRe.FindStringSubmatch(bound)
+		if match == nil {
+			// DEFAULT или MINVALUE/MAXVALUE партиции в кэш не попадают
+			continue
+		}
+		start, err1 := parseBound(match[1])
+		end, err2 := parseBound(match[2])
+		if err1 != nil || err2 != nil {
+			m.logger.Warnf("skip partition %s with unparsed bound %q", name, bound)
+			continue
+		}
+		ranges = append(ranges, partitionRange{name: name, start: start, end: end})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read partitions: %w", err)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Before(ranges[j].start) })
+	return ranges, nil
+}
+
+func parseBound(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05-07:00", "2006-01-02 15:04:05-07:00:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad partition bound %q", s)
+}
+
+// Run по расписанию создаёт партиции на Ahead периодов вперёд и применяет retention
+func (m *PartitionManager) Run(ctx context.Context, tables []string) {
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, table := range tables {
+			m.maintain(ctx, table, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			m.logger.Infof("partition manager stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *PartitionManager) maintain(ctx context.Context, table string, now time.Time) {
+	// Перечитываем каталог: партиции могли создать или отключить снаружи
+	ranges, err := m.load(ctx, table)
+	if err != nil {
+		m.logger.Errorf("%v", err)
+		return
+	}
+	m.remember(table, ranges, nil)
+
+	policy := m.policy(table)
+	t := now
+	for i := 0; i <= m.cfg.Ahead; i++ {
+		if err := m.Ensure(ctx, table, t); err != nil {
+			m.logger.Errorf("failed to pre-create partition: %v", err)
+			return
+		}
+		_, end := m.bounds(policy.granularity, t)
+		t = end
+	}
+
+	if policy.retention > 0 {
+		m.expire(ctx, table, now.Add(-policy.retention))
+	}
+}
+
+// expire убирает партиции, целиком лежащие раньше cutoff, кроме тех, куда в этом запуске писали старые данные.
+// mu держится на всё время: Ensure не должен отдать из кэша партицию, которую сейчас отключают,
+// createMu - чтобы создание не положило в кэш каталог, прочитанный до отключения.
+func (m *PartitionManager) expire(ctx context.Context, table string, cutoff time.Time) {
+	m.createMu.Lock()
+	defer m.createMu.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var kept []partitionRange
+	for _, r := range m.known[table] {
+		if r.end.After(cutoff) {
+			kept = append(kept, r)
+			continue
+		}
+		if m.touched[r.name] {
+			m.logger.Warnf("partition %s [%s, %s) is past retention but was written in this run, keeping it", r.name, r.start, r.end)
+			kept = append(kept, r)
+			continue
+		}
+
+		if err := m.retire(ctx, table, r.name); err != nil {
+			m.logger.Errorf("failed to %s partition %s: %v", m.cfg.RetentionAction, r.name, err)
+			kept = append(kept, r)
+			continue
+		}
+		m.logger.Infof("partition %s [%s, %s) past retention: %s", r.name, r.start, r.end, m.cfg.RetentionAction)
+	}
+	m.known[table] = kept
+}
+
+func (m *PartitionManager) retire(ctx context.Context, table, partition string) error {
+	var statements []string
+	switch m.cfg.RetentionAction {
+	case RetentionDrop:
+		statements = []string{fmt.Sprintf("DROP TABLE %s", partition)}
+	case RetentionArchive:
+		// Архив - отключённая партиция в отдельной схеме, данные остаются доступны запросами
+		statements = []string{
+			fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table, partition),
+			fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", m.cfg.ArchiveSchema),
+			fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s", partition, m.cfg.ArchiveSchema),
+		}
+	default:
+		statements = []string{fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table, partition)}
+	}
+
+	tx, err := m.client.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, sql := range statements {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"testing"
+	"time"
+)
+
+func testManager(t *testing.T, timezone string) *PartitionManager {
+	t.Helper()
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Skipf("timezone %s: %v", timezone, err)
+	}
+	return &PartitionManager{
+		loc:     loc,
+		cfg:     configs.PartitionsConfig{Granularity: GranularityDay},
+		known:   make(map[string][]partitionRange),
+		touched: make(map[string]bool),
+	}
+}
+
+func TestPartitionBounds(t *testing.T) {
+	utc := testManager(t, "UTC")
+	moscow := testManager(t, "Europe/Moscow")
+	berlin := testManager(t, "Europe/Berlin")
+
+	at := func(m *PartitionManager, value string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", value, m.loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	cases := []struct {
+		name        string
+		m           *PartitionManager
+		granularity string
+		t           time.Time
+		start, end  string
+	}{
+		{name: "day", m: utc, granularity: GranularityDay, t: at(utc, "2024-03-05 13:45"), start: "2024-03-05 00:00", end: "2024-03-06 00:00"},
+		{name: "day at midnight", m: utc, granularity: GranularityDay, t: at(utc, "2024-03-05 00:00"), start: "2024-03-05 00:00", end: "2024-03-06 00:00"},
+		{name: "empty granularity is day", m: utc, granularity: "", t: at(utc, "2024-03-05 23:59"), start: "2024-03-05 00:00", end: "2024-03-06 00:00"},
+		{name: "day in zone", m: moscow, granularity: GranularityDay, t: time.Date(2024, 3, 4, 22, 30, 0, 0, time.UTC), start: "2024-03-05 00:00", end: "2024-03-06 00:00"},
+		{name: "week from monday", m: utc, granularity: GranularityWeek, t: at(utc, "2024-03-07 10:00"), start: "2024-03-04 00:00", end: "2024-03-11 00:00"},
+		{name: "week on sunday", m: utc, granularity: GranularityWeek, t: at(utc, "2024-03-10 23:00"), start: "2024-03-04 00:00", end: "2024-03-11 00:00"},
+		{name: "week across year", m: utc, granularity: GranularityWeek, t: at(utc, "2025-01-01 00:00"), start: "2024-12-30 00:00", end: "2025-01-06 00:00"},
+		{name: "month", m: utc, granularity: GranularityMonth, t: at(utc, "2024-02-29 12:00"), start: "2024-02-01 00:00", end: "2024-03-01 00:00"},
+		{name: "month across year", m: utc, granularity: GranularityMonth, t: at(utc, "2024-12-31 23:59"), start: "2024-12-01 00:00", end: "2025-01-01 00:00"},
+		{name: "dst day is 23 hours", m: berlin, granularity: GranularityDay, t: at(berlin, "2024-03-31 12:00"), start: "2024-03-31 00:00", end: "2024-04-01 00:00"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := tc.m.bounds(tc.granularity, tc.t)
+			if want := at(tc.m, tc.start); !start.Equal(want) {
+				t.Fatalf("start %s, want %s", start, want)
+			}
+			if want := at(tc.m, tc.end); !end.Equal(want) {
+				t.Fatalf("end %s, want %s", end, want)
+			}
+			if start.After(tc.t) || !end.After(tc.t) {
+				t.Fatalf("[%s, %s) does not contain %s", start, end, tc.t)
+			}
+		})
+	}
+}
+
+func TestCovering(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	// Партиции 1-3 и 5-6 марта, 4 марта пропущено
+	ranges := []partitionRange{
+		{name: "p1", start: day(1), end: day(2)},
+		{name: "p2", start: day(2), end: day(4)},
+		{name: "p3", start: day(5), end: day(6)},
+	}
+
+	cases := []struct {
+		name  string
+		t     time.Time
+		index int
+		found bool
+	}{
+		{name: "before first", t: day(1).Add(-time.Second), index: 0},
+		{name: "first start is inclusive", t: day(1), index: 0, found: true},
+		{name: "end is exclusive", t: day(2), index: 1, found: true},
+		{name: "inside", t: day(3).Add(time.Hour), index: 1, found: true},
+		{name: "in the gap", t: day(4).Add(time.Hour), index: 2},
+		{name: "last partition", t: day(6).Add(-time.Nanosecond), index: 2, found: true},
+		{name: "after last", t: day(6), index: 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			index, found := covering(ranges, tc.t)
+			if index != tc.index || found != tc.found {
+				t.Fatalf("covering(%s) = %d, %t, want %d, %t", tc.t, index, found, tc.index, tc.found)
+			}
+		})
+	}
+
+	if _, found := covering(nil, day(1)); found {
+		t.Fatal("empty cache covers nothing")
+	}
+}
+
+func TestTouchOnlyMarksPartitionsPastRetention(t *testing.T) {
+	m := testManager(t, "UTC")
+	m.cfg.Retention = 7 * 24 * time.Hour
+	now := time.Now()
+
+	old := partitionRange{name: "old", start: now.AddDate(0, 0, -30), end: now.AddDate(0, 0, -29)}
+	current := partitionRange{name: "current", start: now.Add(-time.Hour), end: now.Add(time.Hour)}
+	m.touch("ethereum_block_metrics", old)
+	m.touch("ethereum_block_metrics", current)
+
+	if !m.touched["old"] || m.touched["current"] {
+		t.Fatalf("touched %v, want only the partition past retention", m.touched)
+	}
+}
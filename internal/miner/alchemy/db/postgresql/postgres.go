@@ -1,6 +1,7 @@
 package db
 
 import (
+	"blocks_gas_validators/internal/metrics"
 	"blocks_gas_validators/internal/miner/alchemy"
 	"blocks_gas_validators/pkg/client/postgresql"
 	"blocks_gas_validators/pkg/logging"
@@ -15,14 +16,19 @@ import (
 )
 
 type repository struct {
-	client postgresql.Client
-	logger *logging.Logger
+	client     postgresql.Client
+	partitions *PartitionManager
+	logger     *logging.Logger
+	// transactions - дописывать транзакции блоков в <chain>_transactions
+	transactions bool
 }
 
-func NewRepository(client postgresql.Client, logger *logging.Logger) alchemy.Storage {
+func NewRepository(client postgresql.Client, partitions *PartitionManager, transactions bool, logger *logging.Logger) alchemy.Storage {
 	return &repository{
-		client: client,
-		logger: logger,
+		client:       client,
+		partitions:   partitions,
+		logger:       logger,
+		transactions: transactions,
 	}
 }
 
@@ -30,47 +36,20 @@ func formatQuery(q string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(q, "\t", ""), "\n", " ")
 }
 
-func (r *repository) EnsurePartitionExists(ctx context.Context, table string, t time.Time) error {
-	loc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		return fmt.Errorf("failed to load location: %w", err)
-	}
-
-	t = t.In(loc)
-	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
-	end := start.Add(24 * time.Hour)
-
-	partitionName := fmt.Sprintf("%s_%s", table, start.Format("2006_01_02"))
-
-	sql := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s PARTITION OF %s
-		FOR VALUES FROM (TIMESTAMPTZ '%s') TO (TIMESTAMPTZ '%s');
-	`, partitionName, table,
-		start.Format("2006-01-02 15:04:05-07"),
-		end.Format("2006-01-02 15:04:05-07"))
-
-	_, err = r.client.Exec(ctx, sql)
-	return err
+var blockColumns = []string{
+	"block_number", "block_time",
+	"transactions_count", "block_size_bytes",
+	"gas_limit", "gas_used", "block_fullness",
+	"block_author", "gas_min", "gas_max", "gas_avg",
+	"gas_stddev", "gas_all_prices", "block_timestamp",
+	"unknown_tx_count", "base_fee",
+	"l1_fee_total", "l1_fee_avg", "l1_gas_used",
+	"l1_base_fee_scalar", "l1_blob_base_fee_scalar", "deposit_tx_count",
 }
 
-func (r *repository) Create(ctx context.Context, block *alchemy.Block, chain string) error {
-	table := fmt.Sprintf("%s_block_metrics", chain)
-	if err := r.EnsurePartitionExists(ctx, table, block.BlockTime); err != nil {
-		return fmt.Errorf("ensure partition: %w", err)
-	}
-	q := fmt.Sprintf(`
-		INSERT INTO %s (
-			block_number, block_time,
-			transactions_count, block_size_bytes,
-			gas_limit, gas_used, block_fullness,
-			block_author, gas_min, gas_max, gas_avg,
-			gas_stddev, gas_all_prices, block_timestamp
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-	`, table)
-
-	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
-
-	_, err := r.client.Exec(ctx, q,
+// blockValues возвращает значения в порядке blockColumns
+func blockValues(block *alchemy.Block) []interface{} {
+	values := []interface{}{
 		block.BlockNumber,
 		block.BlockTime,
 		block.TransactionsCount,
@@ -85,7 +64,88 @@ func (r *repository) Create(ctx context.Context, block *alchemy.Block, chain str
 		block.GasStats.Stddev,
 		block.GasStats.AllPrices,
 		block.BlockTimestamp,
+		block.UnknownTxCount,
+		block.BaseFee,
+	}
+
+	// Для не OP-stack сетей L1 колонки остаются NULL
+	if block.L1Fees == nil {
+		return append(values, nil, nil, nil, nil, nil, nil)
+	}
+	return append(values,
+		block.L1Fees.Total,
+		block.L1Fees.Avg,
+		block.L1Fees.GasUsed,
+		block.L1Fees.BaseFeeScalar,
+		block.L1Fees.BlobBaseFeeScalar,
+		block.L1Fees.DepositCount,
 	)
+}
+
+// upsertSet - обновление всех колонок, кроме ключа (block_time, block_number).
+// Повторная запись того же блока перезаписывает метрики, а не падает на первичном ключе.
+func upsertSet() string {
+	updates := make([]string, 0, len(blockColumns)-2)
+	for _, col := range blockColumns[2:] {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+	return fmt.Sprintf("ON CONFLICT (block_time, block_number) DO UPDATE SET %s", strings.Join(updates, ", "))
+}
+
+func insertBlockQuery(table string) string {
+	placeholders := make([]string, len(blockColumns))
+	for i := range blockColumns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	return fmt.Sprintf(`
+		INSERT INTO %s (%s) VALUES (%s)
+		%s
+	`, table, strings.Join(blockColumns, ", "), strings.Join(placeholders, ", "), upsertSet())
+}
+
+// mergeStagingQuery переносит строки из staging таблицы COPY. DISTINCT ON нужен,
+// потому что ON CONFLICT DO UPDATE не может обновить одну строку дважды за запрос.
+func mergeStagingQuery(table, staging string) string {
+	columns := strings.Join(blockColumns, ", ")
+	return fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		SELECT DISTINCT ON (block_time, block_number) %s FROM %s
+		ORDER BY block_time, block_number
+		%s
+	`, table, columns, columns, staging, upsertSet())
+}
+
+// ensurePartitions проверяет партиции для всех блоков пачки, известные берутся из кэша менеджера
+func (r *repository) ensurePartitions(ctx context.Context, table string, blocks []*alchemy.Block) error {
+	for _, block := range blocks {
+		if err := r.partitions.Ensure(ctx, table, block.BlockTime); err != nil {
+			return fmt.Errorf("ensure partition: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *repository) Create(ctx context.Context, block *alchemy.Block, chain string) error {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	if err := r.partitions.Ensure(ctx, table, block.BlockTime); err != nil {
+		return fmt.Errorf("ensure partition: %w", err)
+	}
+	q := insertBlockQuery(table)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	defer metrics.ObserveInsert(chain, "single", time.Now())
+
+	// Блок и его транзакции пишутся одной транзакцией: иначе при ошибке второй записи
+	// блок остался бы сохранённым без транзакций
+	tx, err := r.client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin block insert: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, q, blockValues(block)...)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -94,6 +154,12 @@ func (r *repository) Create(ctx context.Context, block *alchemy.Block, chain str
 		}
 		return err
 	}
+	if err := r.copyTransactions(ctx, tx, []*alchemy.Block{block}, chain); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit block %d: %w", block.BlockNumber, err)
+	}
 	r.logger.Infof("Successfully inserted block %d into table %s", block.BlockNumber, table)
 
 	return nil
@@ -105,68 +171,27 @@ func (r *repository) InsertBlocksBatch(ctx context.Context, blocks []*alchemy.Bl
 	}
 	table := fmt.Sprintf("%s_block_metrics", chain)
 
-	seen := make(map[string]bool)
-	for _, block := range blocks {
-		dayKey := block.BlockTime.Format("2006-01-02")
-		if !seen[dayKey] {
-			if err := r.EnsurePartitionExists(ctx, table, block.BlockTime); err != nil {
-				return fmt.Errorf("ensure partition for %s: %w", dayKey, err)
-			}
-			seen[dayKey] = true
-		}
+	if err := r.ensurePartitions(ctx, table, blocks); err != nil {
+		return err
 	}
 
-	rows := make([][]interface{}, len(blocks))
-	for i, block := range blocks {
-		rows[i] = []interface{}{
-			block.BlockNumber,
-			block.BlockTime,
-			block.TransactionsCount,
-			block.BlockSizeBytes,
-			block.GasLimit,
-			block.GasUsed,
-			block.BlockFullness,
-			block.Validator,
-			block.GasStats.Min,
-			block.GasStats.Max,
-			block.GasStats.Avg,
-			block.GasStats.Stddev,
-			block.GasStats.AllPrices,
-			block.BlockTimestamp,
-		}
-	}
+	defer metrics.ObserveInsert(chain, "batch", time.Now())
 
-	q := fmt.Sprintf(`
-		INSERT INTO %s (
-			block_number, block_time,
-			transactions_count, block_size_bytes,
-			gas_limit, gas_used, block_fullness,
-			block_author, gas_min, gas_max, gas_avg,
-			gas_stddev, gas_all_prices, block_timestamp
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-	`, table)
+	q := insertBlockQuery(table)
 
 	batch := &pgx.Batch{}
 	for _, block := range blocks {
-		batch.Queue(q,
-			block.BlockNumber,
-			block.BlockTime,
-			block.TransactionsCount,
-			block.BlockSizeBytes,
-			block.GasLimit,
-			block.GasUsed,
-			block.BlockFullness,
-			block.Validator,
-			block.GasStats.Min,
-			block.GasStats.Max,
-			block.GasStats.Avg,
-			block.GasStats.Stddev,
-			block.GasStats.AllPrices,
-			block.BlockTimestamp,
-		)
-	}
-
-	br := r.client.SendBatch(ctx, batch)
+		batch.Queue(q, blockValues(block)...)
+	}
+
+	// Как и в InsertBlocksCopy, блоки и транзакции фиксируются вместе
+	tx, err := r.client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin batch transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	br := tx.SendBatch(ctx, batch)
 	defer br.Close()
 
 	for range blocks {
@@ -176,66 +201,142 @@ func (r *repository) InsertBlocksBatch(ctx context.Context, blocks []*alchemy.Bl
 			return fmt.Errorf("batch insert failed: %w", err)
 		}
 	}
+	// Батч надо закрыть до следующего запроса
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("batch insert failed: %w", err)
+	}
+
+	if err := r.copyTransactions(ctx, tx, blocks, chain); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit batch insert: %w", err)
+	}
 
 	r.logger.Infof("Successfully inserted %d blocks into table %s", len(blocks), table)
 	return nil
 }
 
+// InsertBlocksCopy грузит пачку COPY во временную таблицу и сливает её в основную с upsert:
+// сам COPY не умеет ON CONFLICT, а пересекающиеся прогоны истории не должны падать.
 func (r *repository) InsertBlocksCopy(ctx context.Context, blocks []*alchemy.Block, chain string) error {
 	if len(blocks) == 0 {
 		return nil
 	}
 	table := fmt.Sprintf("%s_block_metrics", chain)
+	staging := fmt.Sprintf("%s_staging", table)
 
 	// Создаём нужные партиции
-	seen := make(map[string]bool)
-	for _, block := range blocks {
-		dayKey := block.BlockTime.Format("2006-01-02")
-		if !seen[dayKey] {
-			if err := r.EnsurePartitionExists(ctx, table, block.BlockTime); err != nil {
-				return fmt.Errorf("ensure partition for %s: %w", dayKey, err)
-			}
-			seen[dayKey] = true
-		}
+	if err := r.ensurePartitions(ctx, table, blocks); err != nil {
+		return err
 	}
 
 	// Готовим данные к вставке
 	rows := make([][]interface{}, len(blocks))
 	for i, block := range blocks {
-		rows[i] = []interface{}{
-			block.BlockNumber,
-			block.BlockTime,
-			block.TransactionsCount,
-			block.BlockSizeBytes,
-			block.GasLimit,
-			block.GasUsed,
-			block.BlockFullness,
-			block.Validator,
-			block.GasStats.Min,
-			block.GasStats.Max,
-			block.GasStats.Avg,
-			block.GasStats.Stddev,
-			block.GasStats.AllPrices,
-			block.BlockTimestamp,
-		}
+		rows[i] = blockValues(block)
 	}
 
-	_, err := r.client.CopyFrom(ctx,
-		pgx.Identifier{table},
-		[]string{
-			"block_number", "block_time",
-			"transactions_count", "block_size_bytes",
-			"gas_limit", "gas_used", "block_fullness",
-			"block_author", "gas_min", "gas_max", "gas_avg",
-			"gas_stddev", "gas_all_prices", "block_timestamp",
-		},
-		pgx.CopyFromRows(rows),
-	)
+	defer metrics.ObserveInsert(chain, "copy", time.Now())
+
+	tx, err := r.client.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("begin copy transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Временная таблица живёт до конца транзакции и видна только этому соединению
+	createStaging := fmt.Sprintf(`
+		CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP
+	`, staging, table)
+	if _, err := tx.Exec(ctx, createStaging); err != nil {
+		return fmt.Errorf("create staging table: %w", err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, blockColumns, pgx.CopyFromRows(rows)); err != nil {
 		r.logger.Error("copy insert failed: " + err.Error())
 		return fmt.Errorf("copy insert failed: %w", err)
 	}
 
+	q := mergeStagingQuery(table, staging)
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	if _, err := tx.Exec(ctx, q); err != nil {
+		r.logger.Error("merge staging failed: " + err.Error())
+		return fmt.Errorf("merge staging failed: %w", err)
+	}
+
+	// Транзакции блоков в той же транзакции: блок и его транзакции видны вместе
+	if err := r.copyTransactions(ctx, tx, blocks, chain); err != nil {
+		r.logger.Error("copy transactions failed: " + err.Error())
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit copy transaction: %w", err)
+	}
+
 	r.logger.Infof("Successfully inserted %d blocks into table %s", len(blocks), table)
 	return nil
 }
+
+// DeleteBlockDuplicates убирает копии блока, записанные с другим block_time: первичный ключ
+// (block_time, block_number) не мешает одному номеру лежать в двух партициях
+func (r *repository) DeleteBlockDuplicates(ctx context.Context, chain string, number uint64, keep time.Time) error {
+	tables := []string{fmt.Sprintf("%s_block_metrics", chain)}
+	if r.transactions {
+		tables = append(tables, fmt.Sprintf("%s_transactions", chain))
+	}
+
+	for _, table := range tables {
+		q := fmt.Sprintf(`
+			DELETE FROM %s WHERE block_number = $1 AND block_time <> $2
+		`, table)
+		r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+		tag, err := r.client.Exec(ctx, q, number, keep)
+		if err != nil {
+			return fmt.Errorf("delete duplicates of block %d from %s: %w", number, table, err)
+		}
+		if tag.RowsAffected() > 0 {
+			r.logger.Infof("deleted %d stale rows of block %d from %s", tag.RowsAffected(), number, table)
+		}
+	}
+	return nil
+}
+
+func (r *repository) InsertMempoolSnapshot(ctx context.Context, snapshot *alchemy.MempoolSnapshot, chain string) error {
+	table := fmt.Sprintf("%s_mempool_snapshots", chain)
+	q := fmt.Sprintf(`
+		INSERT INTO %s (
+			snapshot_time, window_seconds, tx_count,
+			gas_min, gas_max, gas_avg, gas_stddev,
+			gas_p25, gas_p50, gas_p75, gas_p95,
+			tip_p50, tip_p95
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, table)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	_, err := r.client.Exec(ctx, q,
+		snapshot.SnapshotTime,
+		int64(snapshot.Window.Seconds()),
+		snapshot.TxCount,
+		snapshot.GasMin,
+		snapshot.GasMax,
+		snapshot.GasAvg,
+		snapshot.GasStddev,
+		snapshot.GasP25,
+		snapshot.GasP50,
+		snapshot.GasP75,
+		snapshot.GasP95,
+		snapshot.TipP50,
+		snapshot.TipP95,
+	)
+	if err != nil {
+		return fmt.Errorf("insert mempool snapshot: %w", err)
+	}
+	r.logger.Infof("Saved mempool snapshot of %d pending txs into table %s", snapshot.TxCount, table)
+
+	return nil
+}
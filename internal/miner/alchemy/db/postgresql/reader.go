@@ -0,0 +1,405 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/client/postgresql"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewReader(client postgresql.Client, logger *logging.Logger) alchemy.Reader {
+	return &repository{
+		client: client,
+		logger: logger,
+	}
+}
+
+// scanBlock читает строку, выбранную в порядке blockColumns
+func scanBlock(row pgx.Row) (*alchemy.Block, error) {
+	var block alchemy.Block
+	var (
+		l1FeeTotal, l1FeeAvg                  *float64
+		l1GasUsed, l1BaseScalar, l1BlobScalar *int64
+		depositCount                          *int
+		baseFee                               *float64
+	)
+
+	err := row.Scan(
+		&block.BlockNumber,
+		&block.BlockTime,
+		&block.TransactionsCount,
+		&block.BlockSizeBytes,
+		&block.GasLimit,
+		&block.GasUsed,
+		&block.BlockFullness,
+		&block.Validator,
+		&block.GasStats.Min,
+		&block.GasStats.Max,
+		&block.GasStats.Avg,
+		&block.GasStats.Stddev,
+		&block.GasStats.AllPrices,
+		&block.BlockTimestamp,
+		&block.UnknownTxCount,
+		&baseFee,
+		&l1FeeTotal,
+		&l1FeeAvg,
+		&l1GasUsed,
+		&l1BaseScalar,
+		&l1BlobScalar,
+		&depositCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if baseFee != nil {
+		block.BaseFee = *baseFee
+	}
+
+	if l1FeeTotal != nil {
+		block.L1Fees = &alchemy.L1FeeStats{Total: *l1FeeTotal}
+		if l1FeeAvg != nil {
+			block.L1Fees.Avg = *l1FeeAvg
+		}
+		if l1GasUsed != nil {
+			block.L1Fees.GasUsed = uint64(*l1GasUsed)
+		}
+		if l1BaseScalar != nil {
+			block.L1Fees.BaseFeeScalar = uint64(*l1BaseScalar)
+		}
+		if l1BlobScalar != nil {
+			block.L1Fees.BlobBaseFeeScalar = uint64(*l1BlobScalar)
+		}
+		if depositCount != nil {
+			block.L1Fees.DepositCount = *depositCount
+		}
+	}
+
+	return &block, nil
+}
+
+func (r *repository) queryBlocks(ctx context.Context, q string, args ...interface{}) ([]*alchemy.Block, error) {
+	var blocks []*alchemy.Block
+	err := r.streamBlocks(ctx, func(block *alchemy.Block) error {
+		blocks = append(blocks, block)
+		return nil
+	}, q, args...)
+	return blocks, err
+}
+
+// streamBlocks отдаёт строки по одной по мере чтения из курсора, не собирая весь результат в память
+func (r *repository) streamBlocks(ctx context.Context, fn func(*alchemy.Block) error, q string, args ...interface{}) error {
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	rows, err := r.client.Query(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("query blocks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		block, err := scanBlock(rows)
+		if err != nil {
+			return fmt.Errorf("scan block: %w", err)
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read blocks: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) RecentBlocks(ctx context.Context, chain string, limit int) ([]*alchemy.Block, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		ORDER BY block_time DESC, block_number DESC
+		LIMIT $1
+	`, strings.Join(blockColumns, ", "), table)
+
+	return r.queryBlocks(ctx, q, limit)
+}
+
+func (r *repository) BlockByNumber(ctx context.Context, chain string, number uint64) (*alchemy.Block, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_number = $1
+		LIMIT 1
+	`, strings.Join(blockColumns, ", "), table)
+
+	blocks, err := r.queryBlocks(ctx, q, number)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, alchemy.ErrNotFound
+	}
+	return blocks[0], nil
+}
+
+func (r *repository) BlockByNumberNear(ctx context.Context, chain string, number uint64, near time.Time) (*alchemy.Block, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_number = $1 AND block_time BETWEEN $2 AND $3
+		LIMIT 1
+	`, strings.Join(blockColumns, ", "), table)
+
+	// Соседние блоки не бывают дальше суток друг от друга, окно отсекает лишние партиции
+	blocks, err := r.queryBlocks(ctx, q, number, near.Add(-24*time.Hour), near.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, alchemy.ErrNotFound
+	}
+	return blocks[0], nil
+}
+
+func (r *repository) BlocksByRange(ctx context.Context, chain string, from, to uint64, page alchemy.Page) ([]*alchemy.Block, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	args := []interface{}{from, to, page.Limit, page.Offset}
+	var after string
+	if page.After != nil {
+		after = "AND block_number > $5"
+		args = append(args, page.After.BlockNumber)
+	}
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_number BETWEEN $1 AND $2 %s
+		ORDER BY block_number
+		LIMIT $3 OFFSET $4
+	`, strings.Join(blockColumns, ", "), table, after)
+
+	return r.queryBlocks(ctx, q, args...)
+}
+
+func (r *repository) BlocksByTime(ctx context.Context, chain string, from, to time.Time, page alchemy.Page) ([]*alchemy.Block, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	args := []interface{}{from, to, page.Limit, page.Offset}
+	var after string
+	if page.After != nil {
+		after = "AND (block_time, block_number) > ($5, $6)"
+		args = append(args, page.After.BlockTime, page.After.BlockNumber)
+	}
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_time >= $1 AND block_time < $2 %s
+		ORDER BY block_time, block_number
+		LIMIT $3 OFFSET $4
+	`, strings.Join(blockColumns, ", "), table, after)
+
+	return r.queryBlocks(ctx, q, args...)
+}
+
+func (r *repository) StreamBlocksByRange(ctx context.Context, chain string, from, to uint64, fn func(*alchemy.Block) error) error {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_number BETWEEN $1 AND $2
+		ORDER BY block_number
+	`, strings.Join(blockColumns, ", "), table)
+
+	return r.streamBlocks(ctx, fn, q, from, to)
+}
+
+func (r *repository) StreamBlocksByTime(ctx context.Context, chain string, from, to time.Time, fn func(*alchemy.Block) error) error {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE block_time >= $1 AND block_time < $2
+		ORDER BY block_time, block_number
+	`, strings.Join(blockColumns, ", "), table)
+
+	return r.streamBlocks(ctx, fn, q, from, to)
+}
+
+func (r *repository) GasStatsByTime(ctx context.Context, chain string, from, to time.Time) (*alchemy.GasAggregate, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(transactions_count), 0),
+			COALESCE(SUM(gas_used), 0),
+			COALESCE(AVG(block_fullness), 0),
+			COALESCE(MIN(NULLIF(gas_min, 0)), 0),
+			COALESCE(MAX(gas_max), 0),
+			COALESCE(SUM(gas_avg * transactions_count) / NULLIF(SUM(transactions_count), 0), 0),
+			COALESCE(AVG(base_fee), 0)
+		FROM %s
+		WHERE block_time >= $1 AND block_time < $2
+	`, table)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	agg := &alchemy.GasAggregate{From: from, To: to}
+	err := r.client.QueryRow(ctx, q, from, to).Scan(
+		&agg.BlockCount,
+		&agg.TxCount,
+		&agg.GasUsedTotal,
+		&agg.AvgFullness,
+		&agg.GasMin,
+		&agg.GasMax,
+		&agg.GasAvg,
+		&agg.BaseFee,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate gas stats: %w", err)
+	}
+
+	// Перцентили считаем по ценам всех транзакций окна, а не по средним блоков
+	pq := fmt.Sprintf(`
+		SELECT
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY e.price::DOUBLE PRECISION), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY e.price::DOUBLE PRECISION), 0)
+		FROM %s t,
+			LATERAL jsonb_array_elements_text(t.gas_all_prices) AS e(price)
+		WHERE t.block_time >= $1 AND t.block_time < $2
+	`, table)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(pq)))
+
+	if err := r.client.QueryRow(ctx, pq, from, to).Scan(&agg.GasP50, &agg.GasP95); err != nil {
+		return nil, fmt.Errorf("gas percentiles: %w", err)
+	}
+
+	return agg, nil
+}
+
+func (r *repository) ValidatorStatsByTime(ctx context.Context, chain string, from, to time.Time, page alchemy.Page) ([]*alchemy.ValidatorStats, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT
+			block_author,
+			COUNT(*) AS blocks,
+			MIN(block_number),
+			MAX(block_number),
+			MAX(block_time),
+			AVG(block_fullness),
+			AVG(transactions_count),
+			COALESCE(AVG(gas_avg), 0)
+		FROM %s
+		WHERE block_time >= $1 AND block_time < $2
+		GROUP BY block_author
+		ORDER BY blocks DESC, block_author
+		LIMIT $3 OFFSET $4
+	`, table)
+
+	return r.queryValidators(ctx, q, from, to, page.Limit, page.Offset)
+}
+
+// queryValidators читает строки, выбранные в порядке полей ValidatorStats
+func (r *repository) queryValidators(ctx context.Context, q string, args ...interface{}) ([]*alchemy.ValidatorStats, error) {
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	rows, err := r.client.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query validator stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*alchemy.ValidatorStats
+	for rows.Next() {
+		var v alchemy.ValidatorStats
+		if err := rows.Scan(
+			&v.Validator,
+			&v.Blocks,
+			&v.FirstBlock,
+			&v.LastBlock,
+			&v.LastSeen,
+			&v.AvgFullness,
+			&v.AvgTxCount,
+			&v.AvgGasPrice,
+		); err != nil {
+			return nil, fmt.Errorf("scan validator stats: %w", err)
+		}
+		stats = append(stats, &v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read validator stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (r *repository) TopValidators(ctx context.Context, chain string, page alchemy.Page) ([]*alchemy.ValidatorStats, error) {
+	table := fmt.Sprintf("%s_validators", chain)
+	q := fmt.Sprintf(`
+		SELECT
+			address,
+			blocks_total,
+			first_block,
+			last_block,
+			last_seen,
+			COALESCE(avg_fullness, 0),
+			COALESCE(avg_tx_count, 0),
+			COALESCE(avg_gas_price, 0)
+		FROM %s
+		ORDER BY blocks_total DESC, address
+		LIMIT $1 OFFSET $2
+	`, table)
+
+	return r.queryValidators(ctx, q, page.Limit, page.Offset)
+}
+
+func (r *repository) LatestBlock(ctx context.Context, chain string) (*alchemy.Block, error) {
+	blocks, err := r.RecentBlocks(ctx, chain, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, alchemy.ErrNotFound
+	}
+	return blocks[0], nil
+}
+
+// MissingRanges ищет дыры оконной функцией по номерам диапазона. Границы from-1 и to+1
+// добавлены как виртуальные строки, чтобы пропуски в начале и в конце тоже нашлись.
+func (r *repository) MissingRanges(ctx context.Context, chain string, from, to uint64) ([]alchemy.BlockRange, error) {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	q := fmt.Sprintf(`
+		SELECT prev + 1, next - 1
+		FROM (
+			SELECT n AS prev, LEAD(n) OVER (ORDER BY n) AS next
+			FROM (
+				SELECT block_number AS n FROM %s WHERE block_number BETWEEN $1 AND $2
+				UNION ALL SELECT $1::BIGINT - 1
+				UNION ALL SELECT $2::BIGINT + 1
+			) numbers
+		) gaps
+		WHERE next > prev + 1
+		ORDER BY prev
+	`, table)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	rows, err := r.client.Query(ctx, q, int64(from), int64(to))
+	if err != nil {
+		return nil, fmt.Errorf("query missing ranges: %w", err)
+	}
+	defer rows.Close()
+
+	var ranges []alchemy.BlockRange
+	for rows.Next() {
+		var start, end int64
+		if err := rows.Scan(&start, &end); err != nil {
+			return nil, fmt.Errorf("scan missing range: %w", err)
+		}
+		ranges = append(ranges, alchemy.BlockRange{From: uint64(start), To: uint64(end)})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read missing ranges: %w", err)
+	}
+	return ranges, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (r *repository) RefreshRollups(ctx context.Context, chain string, granularity alchemy.Granularity, from, to time.Time) error {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	rollupTable := fmt.Sprintf("%s_gas_rollups", chain)
+
+	// Перцентили считаются отдельным подзапросом: разворачивание gas_all_prices
+	// размножило бы строки блоков и сломало count/avg
+	q := fmt.Sprintf(`
+		INSERT INTO %[2]s (
+			granularity, bucket_start,
+			block_count, tx_count, gas_used_total, avg_fullness,
+			gas_min, gas_max, gas_avg, gas_p50, gas_p95, updated_at
+		)
+		SELECT
+			$1, b.bucket,
+			b.block_count, b.tx_count, b.gas_used_total, b.avg_fullness,
+			b.gas_min, b.gas_max, b.gas_avg,
+			COALESCE(p.gas_p50, 0), COALESCE(p.gas_p95, 0), now()
+		FROM (
+			SELECT
+				date_trunc($2, block_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+				COUNT(*) AS block_count,
+				COALESCE(SUM(transactions_count), 0) AS tx_count,
+				COALESCE(SUM(gas_used), 0) AS gas_used_total,
+				COALESCE(AVG(block_fullness), 0) AS avg_fullness,
+				COALESCE(MIN(NULLIF(gas_min, 0)), 0) AS gas_min,
+				COALESCE(MAX(gas_max), 0) AS gas_max,
+				COALESCE(SUM(gas_avg * transactions_count) / NULLIF(SUM(transactions_count), 0), 0) AS gas_avg
+			FROM %[1]s
+			WHERE block_time >= $3 AND block_time < $4
+			GROUP BY 1
+		) b
+		LEFT JOIN (
+			SELECT
+				date_trunc($2, t.block_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY e.price::DOUBLE PRECISION) AS gas_p50,
+				percentile_cont(0.95) WITHIN GROUP (ORDER BY e.price::DOUBLE PRECISION) AS gas_p95
+			FROM %[1]s t,
+				LATERAL jsonb_array_elements_text(t.gas_all_prices) AS e(price)
+			WHERE t.block_time >= $3 AND t.block_time < $4
+			GROUP BY 1
+		) p ON p.bucket = b.bucket
+		ON CONFLICT (granularity, bucket_start) DO UPDATE SET
+			block_count = EXCLUDED.block_count,
+			tx_count = EXCLUDED.tx_count,
+			gas_used_total = EXCLUDED.gas_used_total,
+			avg_fullness = EXCLUDED.avg_fullness,
+			gas_min = EXCLUDED.gas_min,
+			gas_max = EXCLUDED.gas_max,
+			gas_avg = EXCLUDED.gas_avg,
+			gas_p50 = EXCLUDED.gas_p50,
+			gas_p95 = EXCLUDED.gas_p95,
+			updated_at = EXCLUDED.updated_at
+	`, table, rollupTable)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	tag, err := r.client.Exec(ctx, q, granularity.Name, granularity.Unit, from, to)
+	if err != nil {
+		return fmt.Errorf("refresh %s rollups for %s: %w", granularity.Name, chain, err)
+	}
+	r.logger.Debugf("refreshed %d %s rollup buckets in %s", tag.RowsAffected(), granularity.Name, rollupTable)
+
+	return nil
+}
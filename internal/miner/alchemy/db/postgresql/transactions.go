@@ -0,0 +1,101 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var transactionColumns = []string{
+	"block_time", "block_number", "tx_index",
+	"tx_hash", "from_address", "to_address", "tx_type",
+	"gas_price", "max_fee_per_gas", "max_priority_fee_per_gas", "tip",
+}
+
+// nullable - пустой адрес (создание контракта) пишется как NULL
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func transactionRows(blocks []*alchemy.Block) [][]interface{} {
+	var rows [][]interface{}
+	for _, block := range blocks {
+		for _, tx := range block.Transactions {
+			rows = append(rows, []interface{}{
+				block.BlockTime,
+				block.BlockNumber,
+				tx.TxIndex,
+				tx.Hash,
+				nullable(tx.From),
+				nullable(tx.To),
+				tx.Type,
+				tx.GasPrice,
+				tx.MaxFeePerGas,
+				tx.MaxPriorityFeePerGas,
+				tx.Tip,
+			})
+		}
+	}
+	return rows
+}
+
+func mergeTransactionsQuery(table, staging string) string {
+	columns := strings.Join(transactionColumns, ", ")
+	updates := make([]string, 0, len(transactionColumns)-3)
+	for _, col := range transactionColumns[3:] {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+	}
+
+	return fmt.Sprintf(`
+		INSERT INTO %s (%s)
+		SELECT DISTINCT ON (block_time, block_number, tx_index) %s FROM %s
+		ORDER BY block_time, block_number, tx_index
+		ON CONFLICT (block_time, block_number, tx_index) DO UPDATE SET %s
+	`, table, columns, columns, staging, strings.Join(updates, ", "))
+}
+
+// copyTransactions грузит транзакции пачки через COPY в рамках транзакции tx, в которой
+// пишутся и сами блоки: staging таблица и upsert, повторная запись безопасна
+func (r *repository) copyTransactions(ctx context.Context, tx pgx.Tx, blocks []*alchemy.Block, chain string) error {
+	if !r.transactions {
+		return nil
+	}
+	rows := transactionRows(blocks)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	table := fmt.Sprintf("%s_transactions", chain)
+	staging := fmt.Sprintf("%s_staging", table)
+
+	if err := r.ensurePartitions(ctx, table, blocks); err != nil {
+		return err
+	}
+
+	createStaging := fmt.Sprintf(`
+		CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP
+	`, staging, table)
+	if _, err := tx.Exec(ctx, createStaging); err != nil {
+		return fmt.Errorf("create transactions staging table: %w", err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, transactionColumns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("copy transactions failed: %w", err)
+	}
+
+	q := mergeTransactionsQuery(table, staging)
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	if _, err := tx.Exec(ctx, q); err != nil {
+		return fmt.Errorf("merge transactions failed: %w", err)
+	}
+
+	r.logger.Debugf("Successfully inserted %d transactions into table %s", len(rows), table)
+	return nil
+}
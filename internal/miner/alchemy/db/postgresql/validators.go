@@ -0,0 +1,129 @@
+package db
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"fmt"
+	"time"
+)
+
+// UpdateValidators добавляет блоки пачки к агрегатам их валидаторов.
+// Учитываются только строки блоков, ещё не отмеченные validator_counted: отметка ставится
+// тем же запросом, поэтому повторная доставка, пересекающиеся прогоны и блоки,
+// пришедшие не по порядку (воркеры истории, backfill, repair), не задваивают счётчики.
+// Значения берутся из сохранённых строк, блоки пачки задают только ключи.
+func (r *repository) UpdateValidators(ctx context.Context, blocks []*alchemy.Block, chain string) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	validatorsTable := fmt.Sprintf("%s_validators", chain)
+
+	times := make([]time.Time, 0, len(blocks))
+	numbers := make([]int64, 0, len(blocks))
+	for _, block := range blocks {
+		times = append(times, block.BlockTime)
+		numbers = append(numbers, int64(block.BlockNumber))
+	}
+
+	q := fmt.Sprintf(`
+		WITH b AS (
+			SELECT * FROM unnest($1::TIMESTAMPTZ[], $2::BIGINT[]) AS t(block_time, block_number)
+		), counted AS (
+			UPDATE %[1]s m SET validator_counted = true
+			FROM b
+			WHERE m.block_time = b.block_time AND m.block_number = b.block_number AND NOT m.validator_counted
+			RETURNING m.block_author, m.block_number, m.block_time, m.block_fullness, m.transactions_count, m.gas_avg
+		)
+		INSERT INTO %[2]s AS v (
+			address, first_block, first_seen, last_block, last_seen,
+			blocks_total, txs_total, avg_fullness, avg_tx_count, avg_gas_price, updated_at
+		)
+		SELECT
+			block_author, MIN(block_number), MIN(block_time), MAX(block_number), MAX(block_time),
+			COUNT(*), COALESCE(SUM(transactions_count), 0), AVG(block_fullness), AVG(transactions_count),
+			SUM(gas_avg * transactions_count) / NULLIF(SUM(transactions_count), 0), now()
+		FROM counted
+		WHERE block_author IS NOT NULL AND block_author <> ''
+		GROUP BY block_author
+		ON CONFLICT (address) DO UPDATE SET
+			first_block = LEAST(v.first_block, EXCLUDED.first_block),
+			first_seen = LEAST(v.first_seen, EXCLUDED.first_seen),
+			last_block = GREATEST(v.last_block, EXCLUDED.last_block),
+			last_seen = GREATEST(v.last_seen, EXCLUDED.last_seen),
+			blocks_total = v.blocks_total + EXCLUDED.blocks_total,
+			txs_total = v.txs_total + EXCLUDED.txs_total,
+			avg_fullness = (COALESCE(v.avg_fullness, 0) * v.blocks_total + COALESCE(EXCLUDED.avg_fullness, 0) * EXCLUDED.blocks_total)
+				/ (v.blocks_total + EXCLUDED.blocks_total),
+			avg_tx_count = (v.txs_total + EXCLUDED.txs_total)::DOUBLE PRECISION / (v.blocks_total + EXCLUDED.blocks_total),
+			avg_gas_price = (COALESCE(v.avg_gas_price, 0) * v.txs_total + COALESCE(EXCLUDED.avg_gas_price, 0) * EXCLUDED.txs_total)
+				/ NULLIF(v.txs_total + EXCLUDED.txs_total, 0),
+			updated_at = EXCLUDED.updated_at
+	`, table, validatorsTable)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	tag, err := r.client.Exec(ctx, q, times, numbers)
+	if err != nil {
+		return fmt.Errorf("update validators of %s: %w", chain, err)
+	}
+	r.logger.Debugf("updated %d validators in %s", tag.RowsAffected(), validatorsTable)
+
+	return nil
+}
+
+// RebuildValidators пересчитывает агрегаты всех валидаторов из сырых блоков.
+// Полный скан таблицы блоков: после reprocess или repair, которые перезаписывают уже учтённые блоки.
+// Таблица собирается заново, поэтому адреса, у которых не осталось блоков, из неё уходят.
+func (r *repository) RebuildValidators(ctx context.Context, chain string) error {
+	table := fmt.Sprintf("%s_block_metrics", chain)
+	validatorsTable := fmt.Sprintf("%s_validators", chain)
+
+	// Отметка и пересчёт в одном запросе видят один снимок: блоки, сохранённые позже,
+	// остаются неотмеченными и достанутся UpdateValidators
+	q := fmt.Sprintf(`
+		WITH counted AS (
+			UPDATE %[1]s SET validator_counted = true WHERE NOT validator_counted
+		)
+		INSERT INTO %[2]s (
+			address, first_block, first_seen, last_block, last_seen,
+			blocks_total, txs_total, avg_fullness, avg_tx_count, avg_gas_price, updated_at
+		)
+		SELECT
+			block_author, MIN(block_number), MIN(block_time), MAX(block_number), MAX(block_time),
+			COUNT(*), COALESCE(SUM(transactions_count), 0), AVG(block_fullness), AVG(transactions_count),
+			SUM(gas_avg * transactions_count) / NULLIF(SUM(transactions_count), 0), now()
+		FROM %[1]s
+		WHERE block_author IS NOT NULL AND block_author <> ''
+		GROUP BY block_author
+	`, table, validatorsTable)
+
+	r.logger.Trace(fmt.Sprintf("SQL Query: %s", formatQuery(q)))
+
+	tx, err := r.client.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin validators rebuild: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Параллельный UpdateValidators мог бы прибавить блок после снимка пересчёта,
+	// а пересчёт затёр бы эту прибавку: блокируем обновления до коммита
+	if _, err := tx.Exec(ctx, fmt.Sprintf("LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE", validatorsTable)); err != nil {
+		return fmt.Errorf("lock %s: %w", validatorsTable, err)
+	}
+	// Читатели до коммита видят старые агрегаты, после - только пересчитанные
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s", validatorsTable)); err != nil {
+		return fmt.Errorf("clear %s: %w", validatorsTable, err)
+	}
+
+	tag, err := tx.Exec(ctx, q)
+	if err != nil {
+		return fmt.Errorf("rebuild validators of %s: %w", chain, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit validators rebuild: %w", err)
+	}
+	r.logger.Infof("rebuilt %d validators in %s", tag.RowsAffected(), validatorsTable)
+
+	return nil
+}
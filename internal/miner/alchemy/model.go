@@ -7,12 +7,33 @@ type Block struct {
 	BlockTime         time.Time `json:"block_time"`
 	BlockTimestamp    uint64    `json:"block_timestamp"`
 	TransactionsCount int       `json:"transactions_count"`
+	UnknownTxCount    int       `json:"unknown_tx_count"`
 	BlockSizeBytes    uint64    `json:"block_size_bytes"`
 	GasLimit          uint64    `json:"gas_limit"`
 	GasUsed           uint64    `json:"gas_used"`
 	BlockFullness     float64   `json:"block_fullness"`
+	BaseFee           float64   `json:"base_fee"`
 	Validator         string    `json:"validator"`
 	GasStats          GasStats  `json:"gas_stats"`
+	// L1Fees заполняется только для OP-stack сетей (optimism, base)
+	L1Fees *L1FeeStats `json:"l1_fees,omitempty"`
+	// Transactions пишутся в <chain>_transactions, в выгрузки и синки не попадают
+	Transactions []Transaction `json:"-"`
+}
+
+// Transaction - газовые поля одной транзакции блока, цены в gwei.
+// MaxFeePerGas и MaxPriorityFeePerGas есть только у EIP-1559 транзакций,
+// Tip - фактические чаевые сверх base fee.
+type Transaction struct {
+	TxIndex              int      `json:"tx_index"`
+	Hash                 string   `json:"hash"`
+	From                 string   `json:"from"`
+	To                   string   `json:"to"`
+	Type                 int      `json:"type"`
+	GasPrice             float64  `json:"gas_price"`
+	MaxFeePerGas         *float64 `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas *float64 `json:"max_priority_fee_per_gas,omitempty"`
+	Tip                  float64  `json:"tip"`
 }
 
 type GasStats struct {
@@ -23,6 +44,51 @@ type GasStats struct {
 	AllPrices []float64 `json:"all_prices"`
 }
 
+type L1FeeStats struct {
+	Total             float64 `json:"l1_fee_total"`
+	Avg               float64 `json:"l1_fee_avg"`
+	GasUsed           uint64  `json:"l1_gas_used"`
+	BaseFeeScalar     uint64  `json:"l1_base_fee_scalar"`
+	BlobBaseFeeScalar uint64  `json:"l1_blob_base_fee_scalar"`
+	DepositCount      int     `json:"deposit_tx_count"`
+}
+
+// PendingTx - транзакция из мемпула, цены в gwei.
+// Для EIP-1559 транзакций GasPrice равен MaxFeePerGas.
+type PendingTx struct {
+	Hash           string    `json:"hash"`
+	Type           string    `json:"type"`
+	GasPrice       float64   `json:"gas_price"`
+	MaxPriorityFee float64   `json:"max_priority_fee"`
+	SeenAt         time.Time `json:"seen_at"`
+}
+
+type MempoolSnapshot struct {
+	SnapshotTime time.Time     `json:"snapshot_time"`
+	Window       time.Duration `json:"window"`
+	TxCount      int           `json:"tx_count"`
+	GasMin       float64       `json:"gas_min"`
+	GasMax       float64       `json:"gas_max"`
+	GasAvg       float64       `json:"gas_avg"`
+	GasStddev    float64       `json:"gas_stddev"`
+	GasP25       float64       `json:"gas_p25"`
+	GasP50       float64       `json:"gas_p50"`
+	GasP75       float64       `json:"gas_p75"`
+	GasP95       float64       `json:"gas_p95"`
+	TipP50       float64       `json:"tip_p50"`
+	TipP95       float64       `json:"tip_p95"`
+}
+
+// MissedSlot - слот без блока между двумя соседними блоками сети
+type MissedSlot struct {
+	Slot            uint64    `json:"slot"`
+	SlotTime        time.Time `json:"slot_time"`
+	PrevBlockNumber uint64    `json:"prev_block_number"`
+	PrevProposer    string    `json:"prev_proposer"`
+	NextBlockNumber uint64    `json:"next_block_number"`
+	NextProposer    string    `json:"next_proposer"`
+}
+
 type JSONBlock struct {
 	Number       string            `json:"number"`
 	Timestamp    string            `json:"timestamp"`
@@ -30,9 +96,26 @@ type JSONBlock struct {
 	Size         string            `json:"size"`
 	GasLimit     string            `json:"gasLimit"`
 	GasUsed      string            `json:"gasUsed"`
+	BaseFee      string            `json:"baseFeePerGas"`
 	Miner        string            `json:"miner"`
 }
 
 type JSONTransaction struct {
-	GasPrice string `json:"gasPrice"`
+	Hash                 string `json:"hash"`
+	From                 string `json:"from"`
+	To                   string `json:"to"`
+	Type                 string `json:"type"`
+	GasPrice             string `json:"gasPrice"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	// BlockNumber пуст, пока транзакция в мемпуле
+	BlockNumber string `json:"blockNumber"`
+}
+
+type JSONReceipt struct {
+	Type                string `json:"type"`
+	L1Fee               string `json:"l1Fee"`
+	L1GasUsed           string `json:"l1GasUsed"`
+	L1BaseFeeScalar     string `json:"l1BaseFeeScalar"`
+	L1BlobBaseFeeScalar string `json:"l1BlobBaseFeeScalar"`
 }
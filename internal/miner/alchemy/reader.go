@@ -0,0 +1,85 @@
+package alchemy
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type Reader interface {
+	RecentBlocks(ctx context.Context, chain string, limit int) ([]*Block, error)
+	BlockByNumber(ctx context.Context, chain string, number uint64) (*Block, error)
+	// BlockByNumberNear ищет блок только в партициях около near, это намного дешевле BlockByNumber
+	BlockByNumberNear(ctx context.Context, chain string, number uint64, near time.Time) (*Block, error)
+	BlocksByRange(ctx context.Context, chain string, from, to uint64, page Page) ([]*Block, error)
+	BlocksByTime(ctx context.Context, chain string, from, to time.Time, page Page) ([]*Block, error)
+	// StreamBlocks* вызывают fn для каждого блока диапазона по порядку, не загружая выборку целиком.
+	// Ошибка из fn прерывает чтение и возвращается как есть.
+	StreamBlocksByRange(ctx context.Context, chain string, from, to uint64, fn func(*Block) error) error
+	StreamBlocksByTime(ctx context.Context, chain string, from, to time.Time, fn func(*Block) error) error
+	GasStatsByTime(ctx context.Context, chain string, from, to time.Time) (*GasAggregate, error)
+	ValidatorStatsByTime(ctx context.Context, chain string, from, to time.Time, page Page) ([]*ValidatorStats, error)
+	// LatestBlock - последний сохранённый блок сети, ErrNotFound для пустой таблицы
+	LatestBlock(ctx context.Context, chain string) (*Block, error)
+	// MissingRanges - диапазоны номеров внутри [from, to], которых нет в базе
+	MissingRanges(ctx context.Context, chain string, from, to uint64) ([]BlockRange, error)
+	// TopValidators - валидаторы за всю историю из <chain>_validators, по числу блоков
+	TopValidators(ctx context.Context, chain string, page Page) ([]*ValidatorStats, error)
+}
+
+// Page - страница выборки. С After выборка продолжается строго после курсора
+// (keyset), это не деградирует на глубоких страницах в отличие от Offset.
+type Page struct {
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+	After  *Cursor `json:"-"`
+}
+
+// Cursor - позиция блока в порядке выборки (block_time, block_number)
+type Cursor struct {
+	BlockTime   time.Time
+	BlockNumber uint64
+}
+
+func CursorOf(block *Block) *Cursor {
+	return &Cursor{BlockTime: block.BlockTime, BlockNumber: block.BlockNumber}
+}
+
+// BlockRange - включительный диапазон номеров блоков
+type BlockRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+func (r BlockRange) Len() uint64 {
+	return r.To - r.From + 1
+}
+
+type GasAggregate struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	BlockCount   int64     `json:"block_count"`
+	TxCount      int64     `json:"tx_count"`
+	GasUsedTotal int64     `json:"gas_used_total"`
+	AvgFullness  float64   `json:"avg_fullness"`
+	GasMin       float64   `json:"gas_min"`
+	GasMax       float64   `json:"gas_max"`
+	// GasAvg взвешен по числу транзакций в блоках
+	GasAvg  float64 `json:"gas_avg"`
+	GasP50  float64 `json:"gas_p50"`
+	GasP95  float64 `json:"gas_p95"`
+	BaseFee float64 `json:"avg_base_fee"`
+}
+
+type ValidatorStats struct {
+	Validator   string    `json:"validator"`
+	Blocks      int64     `json:"blocks"`
+	FirstBlock  uint64    `json:"first_block"`
+	LastBlock   uint64    `json:"last_block"`
+	LastSeen    time.Time `json:"last_seen"`
+	AvgFullness float64   `json:"avg_fullness"`
+	AvgTxCount  float64   `json:"avg_tx_count"`
+	AvgGasPrice float64   `json:"avg_gas_price"`
+}
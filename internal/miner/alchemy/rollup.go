@@ -0,0 +1,21 @@
+package alchemy
+
+import "time"
+
+// Granularity - шаг агрегации rollup таблиц, бакеты выровнены по UTC
+type Granularity struct {
+	Name string
+	// Unit - единица для date_trunc в Postgres
+	Unit string
+	Step time.Duration
+}
+
+var Granularities = []Granularity{
+	{Name: "1m", Unit: "minute", Step: time.Minute},
+	{Name: "1h", Unit: "hour", Step: time.Hour},
+	{Name: "1d", Unit: "day", Step: 24 * time.Hour},
+}
+
+func (g Granularity) Truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Step)
+}
@@ -0,0 +1,11 @@
+package alchemy
+
+import "context"
+
+// Sink - получатель сохраняемых блоков (Postgres, файлы, брокер сообщений)
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, chain string, blocks []*Block) error
+	// Close дописывает буферы; Write после Close возвращает ошибку, а не пишет
+	Close() error
+}
@@ -1,9 +1,23 @@
 package alchemy
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// Storage - запись блоков идемпотентна: повторная запись блока обновляет его строку
 type Storage interface {
 	InsertBlocksBatch(ctx context.Context, blocks []*Block, chain string) error
 	InsertBlocksCopy(ctx context.Context, blocks []*Block, chain string) error
 	Create(ctx context.Context, block *Block, chain string) error
+	InsertMempoolSnapshot(ctx context.Context, snapshot *MempoolSnapshot, chain string) error
+	// RefreshRollups пересчитывает из сырых блоков все бакеты granularity в [from, to)
+	RefreshRollups(ctx context.Context, chain string, granularity Granularity, from, to time.Time) error
+	InsertMissedSlots(ctx context.Context, slots []*MissedSlot, chain string) error
+	// UpdateValidators добавляет сохранённые блоки к агрегатам <chain>_validators, каждый блок один раз
+	UpdateValidators(ctx context.Context, blocks []*Block, chain string) error
+	// RebuildValidators пересчитывает <chain>_validators целиком из таблицы блоков
+	RebuildValidators(ctx context.Context, chain string) error
+	// DeleteBlockDuplicates удаляет строки блока number с block_time, отличным от keep
+	DeleteBlockDuplicates(ctx context.Context, chain string, number uint64, keep time.Time) error
 }
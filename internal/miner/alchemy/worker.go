@@ -8,4 +8,16 @@ import (
 type Worker interface {
 	LastRun(ctx context.Context, in <-chan *Block)
 	HistoryBatch(ctx context.Context, in <-chan []*Block, wg *sync.WaitGroup)
+	// SaveBatch синхронно сохраняет пачку тем же путём, что и HistoryBatch
+	SaveBatch(ctx context.Context, blocks []*Block) error
+}
+
+// BlockListener получает блоки, которые уже успешно сохранены
+type BlockListener interface {
+	OnBlocksSaved(ctx context.Context, chain string, blocks []*Block)
+}
+
+type MempoolWorker interface {
+	// included - хэши транзакций, попавших в блоки, они убираются из окна сразу
+	Run(ctx context.Context, in <-chan *PendingTx, included <-chan []string)
 }
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"blocks_gas_validators/internal/metrics"
 	"blocks_gas_validators/internal/miner/alchemy"
 	"context"
 	"sync"
@@ -25,18 +26,20 @@ func (s *BlockSaver) HistoryBatch(ctx context.Context, in <-chan []*alchemy.Bloc
 				continue
 			}
 
-			if len(blocks) < 999 {
-				if err := s.DB.InsertBlocksBatch(ctx, blocks, s.Chain); err != nil {
-					s.Logger.Errorf("failed to insert block batch: %v", err)
-					continue
-				}
-			} else {
-
-				if err := s.DB.InsertBlocksCopy(ctx, blocks, s.Chain); err != nil {
-					s.Logger.Errorf("failed to insert block batch: %v", err)
-					continue
-				}
+			if err := s.SaveBatch(ctx, blocks); err != nil {
+				s.Logger.Errorf("failed to insert block batch: %v", err)
 			}
 		}
 	}
 }
+
+// SaveBatch пишет пачку в sink, учитывает её в метриках и уведомляет listeners
+func (s *BlockSaver) SaveBatch(ctx context.Context, blocks []*alchemy.Block) error {
+	if err := s.Sink.Write(ctx, s.Chain, blocks); err != nil {
+		metrics.BlocksFailed.WithLabelValues(s.Chain, "save").Add(float64(len(blocks)))
+		return err
+	}
+	metrics.BlocksSaved.WithLabelValues(s.Chain).Add(float64(len(blocks)))
+	s.notify(ctx, blocks)
+	return nil
+}
@@ -1,22 +1,32 @@
 package worker
 
 import (
+	"blocks_gas_validators/internal/metrics"
 	"blocks_gas_validators/internal/miner/alchemy"
 	"blocks_gas_validators/pkg/logging"
 	"context"
 )
 
 type BlockSaver struct {
-	DB     alchemy.Storage
-	Logger *logging.Logger
-	Chain  string
+	// Sink - куда пишутся блоки; ошибка Sink означает, что блок не сохранён
+	Sink      alchemy.Sink
+	Logger    *logging.Logger
+	Chain     string
+	Listeners []alchemy.BlockListener
 }
 
-func NewBlockSaver(db alchemy.Storage, chain string, logger *logging.Logger) alchemy.Worker {
+func NewBlockSaver(sink alchemy.Sink, chain string, logger *logging.Logger, listeners ...alchemy.BlockListener) alchemy.Worker {
 	return &BlockSaver{
-		DB:     db,
-		Chain:  chain,
-		Logger: logger,
+		Sink:      sink,
+		Chain:     chain,
+		Logger:    logger,
+		Listeners: listeners,
+	}
+}
+
+func (s *BlockSaver) notify(ctx context.Context, blocks []*alchemy.Block) {
+	for _, l := range s.Listeners {
+		l.OnBlocksSaved(ctx, s.Chain, blocks)
 	}
 }
 
@@ -31,9 +41,14 @@ func (s *BlockSaver) LastRun(ctx context.Context, in <-chan *alchemy.Block) {
 				s.Logger.Warnf("block channel closed for chain: %s", s.Chain)
 				return
 			}
-			if err := s.DB.Create(ctx, block, s.Chain); err != nil {
+			if err := s.Sink.Write(ctx, s.Chain, []*alchemy.Block{block}); err != nil {
 				s.Logger.Errorf("failed to save block %d: %v", block.BlockNumber, err)
+				metrics.BlocksFailed.WithLabelValues(s.Chain, "save").Inc()
+				continue
 			}
+			metrics.BlocksSaved.WithLabelValues(s.Chain).Inc()
+			metrics.ObserveSaved(s.Chain, block.BlockNumber)
+			s.notify(ctx, []*alchemy.Block{block})
 		}
 	}
 }
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"blocks_gas_validators/pkg/utilits"
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+type MempoolSaver struct {
+	DB       alchemy.Storage
+	Logger   *logging.Logger
+	Chain    string
+	Window   time.Duration
+	Interval time.Duration
+
+	// pending - скользящее окно транзакций по хэшу, повторные анонсы не дублируются.
+	// Транзакции уходят из окна по возрасту или при включении в блок
+	pending map[string]*alchemy.PendingTx
+}
+
+func NewMempoolSaver(db alchemy.Storage, chain string, window, interval time.Duration, logger *logging.Logger) alchemy.MempoolWorker {
+	return &MempoolSaver{
+		DB:       db,
+		Chain:    chain,
+		Window:   window,
+		Interval: interval,
+		Logger:   logger,
+		pending:  make(map[string]*alchemy.PendingTx),
+	}
+}
+
+func (s *MempoolSaver) Run(ctx context.Context, in <-chan *alchemy.PendingTx, included <-chan []string) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Logger.Infof("mempool saver stopped for chain: %s", s.Chain)
+			return
+
+		case tx, ok := <-in:
+			if !ok {
+				s.Logger.Warnf("pending transactions channel closed for chain: %s", s.Chain)
+				return
+			}
+			if _, exists := s.pending[tx.Hash]; !exists {
+				s.pending[tx.Hash] = tx
+			}
+
+		case hashes, ok := <-included:
+			if !ok {
+				// Без блоков окно продолжает работать, транзакции уходят по возрасту
+				s.Logger.Warnf("included transactions channel closed for chain: %s", s.Chain)
+				included = nil
+				continue
+			}
+			for _, hash := range hashes {
+				delete(s.pending, hash)
+			}
+
+		case now := <-ticker.C:
+			snapshot := s.snapshot(now)
+			if err := s.DB.InsertMempoolSnapshot(ctx, snapshot, s.Chain); err != nil {
+				s.Logger.Errorf("failed to save mempool snapshot: %v", err)
+			}
+		}
+	}
+}
+
+// snapshot вытесняет транзакции старше окна и считает распределение по оставшимся
+func (s *MempoolSaver) snapshot(now time.Time) *alchemy.MempoolSnapshot {
+	cutoff := now.Add(-s.Window)
+
+	prices := make([]float64, 0, len(s.pending))
+	tips := make([]float64, 0, len(s.pending))
+	for hash, tx := range s.pending {
+		if tx.SeenAt.Before(cutoff) {
+			delete(s.pending, hash)
+			continue
+		}
+		prices = append(prices, tx.GasPrice)
+		tips = append(tips, tx.MaxPriorityFee)
+	}
+
+	snapshot := &alchemy.MempoolSnapshot{
+		SnapshotTime: now,
+		Window:       s.Window,
+		TxCount:      len(prices),
+	}
+	if len(prices) == 0 {
+		return snapshot
+	}
+
+	sort.Float64s(prices)
+	sort.Float64s(tips)
+
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	avg := sum / float64(len(prices))
+
+	var sumSq float64
+	for _, p := range prices {
+		diff := p - avg
+		sumSq += diff * diff
+	}
+
+	snapshot.GasMin = prices[0]
+	snapshot.GasMax = prices[len(prices)-1]
+	snapshot.GasAvg = avg
+	snapshot.GasStddev = math.Sqrt(sumSq / float64(len(prices)))
+	snapshot.GasP25 = utilits.Percentile(prices, 25)
+	snapshot.GasP50 = utilits.Percentile(prices, 50)
+	snapshot.GasP75 = utilits.Percentile(prices, 75)
+	snapshot.GasP95 = utilits.Percentile(prices, 95)
+	snapshot.TipP50 = utilits.Percentile(tips, 50)
+	snapshot.TipP95 = utilits.Percentile(tips, 95)
+
+	return snapshot
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/chains"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MissedSlotDetector ищет пропущенные слоты между соседними по номеру блоками.
+// Соседа, которого нет в пришедшей пачке, берём из уже сохранённых блоков,
+// поэтому работает и для live, и для history (в том числе для бэкфиллов в середину).
+type MissedSlotDetector struct {
+	DB     alchemy.Storage
+	Reader alchemy.Reader
+	Logger *logging.Logger
+	Chain  string
+	Info   chains.ChainInfo
+
+	mu sync.Mutex
+	// last - последний блок live режима, чтобы не ходить в базу за предыдущим
+	last *alchemy.Block
+}
+
+func NewMissedSlotDetector(db alchemy.Storage, reader alchemy.Reader, chain string, logger *logging.Logger) *MissedSlotDetector {
+	return &MissedSlotDetector{
+		DB:     db,
+		Reader: reader,
+		Logger: logger,
+		Chain:  chain,
+		Info:   chains.AlchemyChains[chain],
+	}
+}
+
+func (d *MissedSlotDetector) OnBlocksSaved(ctx context.Context, chain string, blocks []*alchemy.Block) {
+	if chain != d.Chain || d.Info.SlotGenesis == 0 || len(blocks) == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sorted := make([]*alchemy.Block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BlockNumber < sorted[j].BlockNumber })
+
+	byNumber := make(map[uint64]*alchemy.Block, len(sorted))
+	for _, b := range sorted {
+		byNumber[b.BlockNumber] = b
+	}
+
+	var missed []*alchemy.MissedSlot
+	for _, block := range sorted {
+		prev, ok := byNumber[block.BlockNumber-1]
+		if !ok {
+			prev = d.neighbour(ctx, block.BlockNumber-1, block.BlockTime)
+		}
+		if prev != nil {
+			missed = append(missed, d.between(prev, block)...)
+		}
+
+		// Бэкфилл в середину: следующий блок мог быть сохранён раньше
+		if _, ok := byNumber[block.BlockNumber+1]; !ok {
+			if next := d.neighbour(ctx, block.BlockNumber+1, block.BlockTime); next != nil {
+				missed = append(missed, d.between(block, next)...)
+			}
+		}
+	}
+
+	last := sorted[len(sorted)-1]
+	if d.last == nil || last.BlockNumber > d.last.BlockNumber {
+		d.last = last
+	}
+
+	if err := d.DB.InsertMissedSlots(ctx, missed, d.Chain); err != nil {
+		d.Logger.Errorf("failed to save missed slots: %v", err)
+	}
+}
+
+func (d *MissedSlotDetector) neighbour(ctx context.Context, number uint64, near time.Time) *alchemy.Block {
+	if d.last != nil && d.last.BlockNumber == number {
+		return d.last
+	}
+
+	block, err := d.Reader.BlockByNumberNear(ctx, d.Chain, number, near)
+	if errors.Is(err, alchemy.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		d.Logger.Warnf("failed to load block %d for missed slot check: %v", number, err)
+		return nil
+	}
+	return block
+}
+
+// between возвращает слоты строго между двумя соседними блоками
+func (d *MissedSlotDetector) between(prev, next *alchemy.Block) []*alchemy.MissedSlot {
+	if prev.BlockNumber < d.Info.SlotsFromBlock {
+		return nil
+	}
+
+	prevSlot := d.Info.SlotAt(prev.BlockTimestamp)
+	nextSlot := d.Info.SlotAt(next.BlockTimestamp)
+
+	var missed []*alchemy.MissedSlot
+	for slot := prevSlot + 1; slot < nextSlot; slot++ {
+		missed = append(missed, &alchemy.MissedSlot{
+			Slot:            slot,
+			SlotTime:        time.Unix(d.Info.SlotTime(slot), 0),
+			PrevBlockNumber: prev.BlockNumber,
+			PrevProposer:    prev.Validator,
+			NextBlockNumber: next.BlockNumber,
+			NextProposer:    next.Validator,
+		})
+	}
+	return missed
+}
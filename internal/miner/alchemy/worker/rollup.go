@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const maxRollupBuckets = 500
+
+// RollupUpdater помечает бакеты, в которые попали сохранённые блоки, и периодически
+// пересчитывает их из сырой таблицы. Так же обрабатываются и бэкфиллы истории:
+// блоки в уже агрегированном окне просто снова делают бакет грязным.
+type RollupUpdater struct {
+	DB     alchemy.Storage
+	Logger *logging.Logger
+	Chain  string
+	// Intervals - как часто сбрасывать грязные бакеты каждой гранулярности
+	Intervals map[string]time.Duration
+
+	mu    sync.Mutex
+	dirty map[string]map[time.Time]struct{}
+}
+
+func NewRollupUpdater(db alchemy.Storage, chain string, intervals map[string]time.Duration, logger *logging.Logger) *RollupUpdater {
+	dirty := make(map[string]map[time.Time]struct{}, len(alchemy.Granularities))
+	for _, g := range alchemy.Granularities {
+		dirty[g.Name] = make(map[time.Time]struct{})
+	}
+
+	return &RollupUpdater{
+		DB:        db,
+		Chain:     chain,
+		Intervals: intervals,
+		Logger:    logger,
+		dirty:     dirty,
+	}
+}
+
+func (u *RollupUpdater) OnBlocksSaved(_ context.Context, chain string, blocks []*alchemy.Block) {
+	if chain != u.Chain {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, block := range blocks {
+		for _, g := range alchemy.Granularities {
+			u.dirty[g.Name][g.Truncate(block.BlockTime)] = struct{}{}
+		}
+	}
+}
+
+func (u *RollupUpdater) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, g := range alchemy.Granularities {
+		interval, ok := u.Intervals[g.Name]
+		if !ok || interval <= 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(g alchemy.Granularity, interval time.Duration) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					// Последний сброс, чтобы не потерять хвост при остановке
+					flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					u.flush(flushCtx, g)
+					cancel()
+					return
+				case <-ticker.C:
+					u.flush(ctx, g)
+				}
+			}
+		}(g, interval)
+	}
+
+	wg.Wait()
+	u.Logger.Infof("rollup updater stopped for chain: %s", u.Chain)
+}
+
+// Flush сразу пересчитывает все грязные бакеты, используется в конце history режима
+func (u *RollupUpdater) Flush(ctx context.Context) {
+	for _, g := range alchemy.Granularities {
+		u.flush(ctx, g)
+	}
+}
+
+func (u *RollupUpdater) flush(ctx context.Context, g alchemy.Granularity) {
+	u.mu.Lock()
+	buckets := make([]time.Time, 0, len(u.dirty[g.Name]))
+	for b := range u.dirty[g.Name] {
+		buckets = append(buckets, b)
+	}
+	u.dirty[g.Name] = make(map[time.Time]struct{})
+	u.mu.Unlock()
+
+	if len(buckets) == 0 {
+		return
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Before(buckets[j]) })
+
+	// Соседние бакеты пересчитываем одним запросом, но не больше maxRollupBuckets за раз,
+	// чтобы бэкфилл за месяц не превращался в один гигантский запрос
+	start, end := buckets[0], buckets[0].Add(g.Step)
+	for _, b := range buckets[1:] {
+		if b.Equal(end) && end.Sub(start) < maxRollupBuckets*g.Step {
+			end = end.Add(g.Step)
+			continue
+		}
+		u.refresh(ctx, g, start, end)
+		start, end = b, b.Add(g.Step)
+	}
+	u.refresh(ctx, g, start, end)
+}
+
+func (u *RollupUpdater) refresh(ctx context.Context, g alchemy.Granularity, from, to time.Time) {
+	if err := u.DB.RefreshRollups(ctx, u.Chain, g, from, to); err != nil {
+		u.Logger.Errorf("failed to refresh %s rollups [%s, %s): %v", g.Name, from, to, err)
+
+		// Вернём окно в грязные, чтобы повторить на следующем тике
+		u.mu.Lock()
+		for b := from; b.Before(to); b = b.Add(g.Step) {
+			u.dirty[g.Name][b] = struct{}{}
+		}
+		u.mu.Unlock()
+	}
+}
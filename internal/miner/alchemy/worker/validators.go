@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+)
+
+// ValidatorTracker обновляет таблицу валидаторов по каждой сохранённой пачке блоков,
+// чтобы запросы по валидаторам не сканировали всю таблицу блоков
+type ValidatorTracker struct {
+	DB     alchemy.Storage
+	Logger *logging.Logger
+	Chain  string
+}
+
+func NewValidatorTracker(db alchemy.Storage, chain string, logger *logging.Logger) *ValidatorTracker {
+	return &ValidatorTracker{
+		DB:     db,
+		Logger: logger,
+		Chain:  chain,
+	}
+}
+
+func (t *ValidatorTracker) OnBlocksSaved(ctx context.Context, chain string, blocks []*alchemy.Block) {
+	if chain != t.Chain {
+		return
+	}
+	if err := t.DB.UpdateValidators(ctx, blocks, t.Chain); err != nil {
+		t.Logger.Errorf("failed to update validators: %v", err)
+	}
+}
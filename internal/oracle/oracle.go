@@ -0,0 +1,156 @@
+package oracle
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/chains"
+	"blocks_gas_validators/pkg/logging"
+	"blocks_gas_validators/pkg/utilits"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrNoData = errors.New("not enough stored blocks to estimate fees")
+
+// Перцентили чаевых внутри блока для уровней slow/standard/fast
+const (
+	slowPercentile     = 25
+	standardPercentile = 50
+	fastPercentile     = 90
+)
+
+type FeeLevel struct {
+	PriorityFee float64 `json:"priority_fee"`
+	MaxFee      float64 `json:"max_fee"`
+	// Confidence - доля блоков выборки, в которые транзакция с такими чаевыми попала бы
+	Confidence float64 `json:"confidence"`
+}
+
+type FeeEstimate struct {
+	Chain            string    `json:"chain"`
+	LatestBlock      uint64    `json:"latest_block"`
+	BaseFeeAvailable bool      `json:"base_fee_available"`
+	NextBaseFee      float64   `json:"next_base_fee"`
+	Slow             FeeLevel  `json:"slow"`
+	Standard         FeeLevel  `json:"standard"`
+	Fast             FeeLevel  `json:"fast"`
+	SampleBlocks     int       `json:"sample_blocks"`
+	GeneratedAt      time.Time `json:"generated_at"`
+}
+
+type FeeOracle struct {
+	reader alchemy.Reader
+	logger *logging.Logger
+	// sampleSize - сколько последних сохранённых блоков берём в выборку
+	sampleSize int
+}
+
+func NewFeeOracle(reader alchemy.Reader, sampleSize int, logger *logging.Logger) *FeeOracle {
+	return &FeeOracle{
+		reader:     reader,
+		logger:     logger,
+		sampleSize: sampleSize,
+	}
+}
+
+func (o *FeeOracle) Estimate(ctx context.Context, chain string) (*FeeEstimate, error) {
+	blocks, err := o.reader.RecentBlocks(ctx, chain, o.sampleSize)
+	if err != nil {
+		return nil, fmt.Errorf("load recent blocks for %s: %w", chain, err)
+	}
+	if len(blocks) == 0 {
+		return nil, ErrNoData
+	}
+
+	latest := blocks[0]
+	estimate := &FeeEstimate{
+		Chain:            chain,
+		LatestBlock:      latest.BlockNumber,
+		BaseFeeAvailable: latest.BaseFee > 0,
+		SampleBlocks:     len(blocks),
+		GeneratedAt:      time.Now(),
+	}
+	if estimate.BaseFeeAvailable {
+		estimate.NextBaseFee = NextBaseFee(latest, chains.AlchemyChains[chain].OPStack)
+	}
+
+	// Чаевые считаем как цену минус base fee блока, в котором транзакция оказалась
+	var slow, standard, fast []float64
+	var minTips []float64
+	for _, block := range blocks {
+		tips := blockTips(block)
+		if len(tips) == 0 {
+			continue
+		}
+		slow = append(slow, utilits.Percentile(tips, slowPercentile))
+		standard = append(standard, utilits.Percentile(tips, standardPercentile))
+		fast = append(fast, utilits.Percentile(tips, fastPercentile))
+		minTips = append(minTips, tips[0])
+	}
+	if len(minTips) == 0 {
+		return nil, ErrNoData
+	}
+
+	estimate.Slow = o.level(slow, minTips, estimate.NextBaseFee)
+	estimate.Standard = o.level(standard, minTips, estimate.NextBaseFee)
+	estimate.Fast = o.level(fast, minTips, estimate.NextBaseFee)
+
+	o.logger.Debugf("fee estimate for %s at block %d from %d blocks", chain, latest.BlockNumber, len(minTips))
+	return estimate, nil
+}
+
+func (o *FeeOracle) level(perBlock, minTips []float64, nextBaseFee float64) FeeLevel {
+	sort.Float64s(perBlock)
+	tip := utilits.Percentile(perBlock, 50)
+
+	var included int
+	for _, m := range minTips {
+		if tip >= m {
+			included++
+		}
+	}
+
+	return FeeLevel{
+		PriorityFee: tip,
+		// Запас в два base fee, как делают кошельки: переживает несколько полных блоков подряд
+		MaxFee:     2*nextBaseFee + tip,
+		Confidence: float64(included) / float64(len(minTips)),
+	}
+}
+
+// blockTips возвращает отсортированные чаевые транзакций блока
+func blockTips(block *alchemy.Block) []float64 {
+	tips := make([]float64, 0, len(block.GasStats.AllPrices))
+	for _, price := range block.GasStats.AllPrices {
+		tip := price - block.BaseFee
+		if tip < 0 {
+			tip = 0
+		}
+		tips = append(tips, tip)
+	}
+	sort.Float64s(tips)
+	return tips
+}
+
+// NextBaseFee предсказывает base fee следующего блока по правилу EIP-1559
+func NextBaseFee(block *alchemy.Block, opStack bool) float64 {
+	elasticity, denominator := 2.0, 8.0
+	if opStack {
+		// Параметры OP-stack после Canyon
+		elasticity, denominator = 6.0, 250.0
+	}
+
+	target := float64(block.GasLimit) / elasticity
+	if target == 0 {
+		return block.BaseFee
+	}
+
+	delta := block.BaseFee * (float64(block.GasUsed) - target) / target / denominator
+	next := block.BaseFee + delta
+	if next < 0 {
+		return 0
+	}
+	return next
+}
@@ -0,0 +1,165 @@
+package oracle
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logging.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return &logging.Logger{Entry: logrus.NewEntry(l)}
+}
+
+// fakeReader отдаёт заданные блоки как последние сохранённые, остальные методы не вызываются
+type fakeReader struct {
+	alchemy.Reader
+	blocks []*alchemy.Block
+	err    error
+}
+
+func (f *fakeReader) RecentBlocks(_ context.Context, _ string, limit int) ([]*alchemy.Block, error) {
+	return f.blocks[:min(limit, len(f.blocks))], f.err
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNextBaseFee(t *testing.T) {
+	const limit = 30_000_000
+
+	cases := []struct {
+		name    string
+		block   alchemy.Block
+		opStack bool
+		want    float64
+	}{
+		{name: "at target", block: alchemy.Block{BaseFee: 10, GasLimit: limit, GasUsed: limit / 2}, want: 10},
+		{name: "full block is +12.5%", block: alchemy.Block{BaseFee: 10, GasLimit: limit, GasUsed: limit}, want: 11.25},
+		{name: "empty block is -12.5%", block: alchemy.Block{BaseFee: 10, GasLimit: limit}, want: 8.75},
+		{name: "three quarters full", block: alchemy.Block{BaseFee: 16, GasLimit: limit, GasUsed: limit * 3 / 4}, want: 17},
+		{name: "zero gas limit keeps base fee", block: alchemy.Block{BaseFee: 10}, want: 10},
+		{name: "zero base fee", block: alchemy.Block{GasLimit: limit, GasUsed: limit}, want: 0},
+		{name: "op stack full block", block: alchemy.Block{BaseFee: 10, GasLimit: 60_000_000, GasUsed: 60_000_000}, opStack: true, want: 10.2},
+		{name: "op stack empty block", block: alchemy.Block{BaseFee: 10, GasLimit: 60_000_000}, opStack: true, want: 9.96},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NextBaseFee(&tc.block, tc.opStack); !near(got, tc.want) {
+				t.Fatalf("next base fee = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextBaseFeeStaysWithinBounds(t *testing.T) {
+	const limit = 30_000_000
+	for used := uint64(0); used <= limit; used += limit / 64 {
+		block := &alchemy.Block{BaseFee: 20, GasLimit: limit, GasUsed: used}
+		if got := NextBaseFee(block, false); got < 20*0.875-1e-9 || got > 20*1.125+1e-9 {
+			t.Fatalf("gas used %d: next base fee %v outside ±12.5%% of 20", used, got)
+		}
+	}
+}
+
+// block - блок с base fee 10 и транзакциями по ценам prices
+func block(number uint64, prices ...float64) *alchemy.Block {
+	return &alchemy.Block{
+		BlockNumber:       number,
+		BaseFee:           10,
+		GasLimit:          30_000_000,
+		GasUsed:           30_000_000,
+		TransactionsCount: len(prices),
+		GasStats:          alchemy.GasStats{AllPrices: prices},
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	reader := &fakeReader{blocks: []*alchemy.Block{
+		// Чаевые 1..5 и 5..9; блок без транзакций в выборку чаевых не попадает
+		block(102, 11, 12, 13, 14, 15),
+		block(101),
+		block(100, 15, 16, 17, 18, 19),
+	}}
+	estimate, err := NewFeeOracle(reader, 10, testLogger()).Estimate(context.Background(), "ethereum")
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+
+	if estimate.LatestBlock != 102 || estimate.SampleBlocks != 3 || !estimate.BaseFeeAvailable {
+		t.Fatalf("unexpected estimate %+v", estimate)
+	}
+	if !near(estimate.NextBaseFee, 11.25) {
+		t.Fatalf("next base fee = %v, want 11.25", estimate.NextBaseFee)
+	}
+
+	// Уровень - медиана по блокам перцентиля чаевых внутри блока
+	levels := []struct {
+		name       string
+		level      FeeLevel
+		tip        float64
+		confidence float64
+	}{
+		{name: "slow", level: estimate.Slow, tip: 4, confidence: 0.5},
+		{name: "standard", level: estimate.Standard, tip: 5, confidence: 1},
+		{name: "fast", level: estimate.Fast, tip: 6.6, confidence: 1},
+	}
+	for _, l := range levels {
+		if !near(l.level.PriorityFee, l.tip) || !near(l.level.Confidence, l.confidence) {
+			t.Fatalf("%s = %+v, want tip %v, confidence %v", l.name, l.level, l.tip, l.confidence)
+		}
+		if want := 2*11.25 + l.tip; !near(l.level.MaxFee, want) {
+			t.Fatalf("%s max fee = %v, want %v", l.name, l.level.MaxFee, want)
+		}
+	}
+}
+
+func TestEstimateWithoutBaseFee(t *testing.T) {
+	b := block(7, 3, 5)
+	b.BaseFee = 0
+	estimate, err := NewFeeOracle(&fakeReader{blocks: []*alchemy.Block{b}}, 10, testLogger()).Estimate(context.Background(), "ethereum")
+	if err != nil {
+		t.Fatalf("estimate: %v", err)
+	}
+	if estimate.BaseFeeAvailable || estimate.NextBaseFee != 0 {
+		t.Fatalf("base fee reported for a legacy chain: %+v", estimate)
+	}
+	// Без base fee чаевые - вся цена
+	if !near(estimate.Standard.PriorityFee, 4) || !near(estimate.Standard.MaxFee, 4) {
+		t.Fatalf("standard = %+v, want tip and max fee 4", estimate.Standard)
+	}
+}
+
+func TestEstimateNoData(t *testing.T) {
+	cases := []struct {
+		name   string
+		blocks []*alchemy.Block
+	}{
+		{name: "empty history"},
+		{name: "blocks without transactions", blocks: []*alchemy.Block{block(2), block(1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewFeeOracle(&fakeReader{blocks: tc.blocks}, 10, testLogger()).Estimate(context.Background(), "ethereum")
+			if !errors.Is(err, ErrNoData) {
+				t.Fatalf("err = %v, want ErrNoData", err)
+			}
+		})
+	}
+}
+
+func TestEstimateReaderError(t *testing.T) {
+	reader := &fakeReader{err: errors.New("connection refused")}
+	_, err := NewFeeOracle(reader, 10, testLogger()).Estimate(context.Background(), "ethereum")
+	if err == nil || errors.Is(err, ErrNoData) {
+		t.Fatalf("err = %v, want the reader error", err)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/oracle"
+	"errors"
+	"net/http"
+)
+
+func (s *Server) RegisterFeeOracle(o *oracle.FeeOracle) {
+	s.HandleFunc("GET /api/v1/chains/{chain}/fees", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		estimate, err := o.Estimate(r.Context(), chain)
+		if errors.Is(err, oracle.ErrNoData) {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, estimate)
+	})
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/health"
+	"net/http"
+)
+
+func (s *Server) RegisterHealth(checker *health.Checker) {
+	s.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, checker.Liveness())
+	})
+
+	s.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, checker.Readiness(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report health.Report) {
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
@@ -0,0 +1,341 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+	defaultWindow    = time.Hour
+)
+
+type pageResponse struct {
+	Items      interface{} `json:"items"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+	NextOffset *int        `json:"next_offset,omitempty"`
+	// NextCursor - продолжение выборки блоков без OFFSET, передаётся в cursor
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+func newPageResponse(items interface{}, count int, page alchemy.Page) pageResponse {
+	resp := pageResponse{Items: items, Limit: page.Limit, Offset: page.Offset}
+	// Полная страница - возможно, дальше есть ещё. Выборку по курсору продолжает только next_cursor
+	if count == page.Limit && page.After == nil {
+		next := page.Offset + page.Limit
+		resp.NextOffset = &next
+	}
+	return resp
+}
+
+func (s *Server) RegisterQueryAPI(reader alchemy.Reader) {
+	s.HandleFunc("GET /api/v1/chains/{chain}/blocks/{number}", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		number, err := strconv.ParseUint(r.PathValue("number"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid block number: %w", err))
+			return
+		}
+
+		block, err := reader.BlockByNumber(r.Context(), chain, number)
+		if errors.Is(err, alchemy.ErrNotFound) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("block %d not found", number))
+			return
+		}
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, block)
+	})
+
+	// Диапазон задаётся либо номерами (from, to), либо временем (from_time, to_time, window)
+	s.HandleFunc("GET /api/v1/chains/{chain}/blocks", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := pageParams(r, true)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var blocks []*alchemy.Block
+		query := r.URL.Query()
+		if query.Has("from") || query.Has("to") {
+			from, to, rangeErr := numberRangeParams(r)
+			if rangeErr != nil {
+				writeError(w, http.StatusBadRequest, rangeErr)
+				return
+			}
+			blocks, err = reader.BlocksByRange(r.Context(), chain, from, to, page)
+		} else {
+			from, to, rangeErr := timeRangeParams(r)
+			if rangeErr != nil {
+				writeError(w, http.StatusBadRequest, rangeErr)
+				return
+			}
+			blocks, err = reader.BlocksByTime(r.Context(), chain, from, to, page)
+		}
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		if blocks == nil {
+			blocks = []*alchemy.Block{}
+		}
+		resp := newPageResponse(blocks, len(blocks), page)
+		if len(blocks) == page.Limit {
+			cursor := encodeCursor(alchemy.CursorOf(blocks[len(blocks)-1]))
+			resp.NextCursor = &cursor
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+
+	s.HandleFunc("GET /api/v1/chains/{chain}/blocks/latest", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		block, err := reader.LatestBlock(r.Context(), chain)
+		if errors.Is(err, alchemy.ErrNotFound) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no blocks stored for %s", chain))
+			return
+		}
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, block)
+	})
+
+	s.HandleFunc("GET /api/v1/chains/{chain}/blocks/missing", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		from, to, err := numberRangeParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		ranges, err := reader.MissingRanges(r.Context(), chain, from, to)
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		if ranges == nil {
+			ranges = []alchemy.BlockRange{}
+		}
+		writeJSON(w, http.StatusOK, ranges)
+	})
+
+	s.HandleFunc("GET /api/v1/chains/{chain}/stats/gas", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		from, to, err := timeRangeParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := reader.GasStatsByTime(r.Context(), chain, from, to)
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, stats)
+	})
+
+	s.HandleFunc("GET /api/v1/chains/{chain}/validators", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := pageParams(r, false)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		from, to, err := timeRangeParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := reader.ValidatorStatsByTime(r.Context(), chain, from, to, page)
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		if stats == nil {
+			stats = []*alchemy.ValidatorStats{}
+		}
+		writeJSON(w, http.StatusOK, newPageResponse(stats, len(stats), page))
+	})
+
+	s.HandleFunc("GET /api/v1/chains/{chain}/validators/top", func(w http.ResponseWriter, r *http.Request) {
+		chain, err := chainParam(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		page, err := pageParams(r, false)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := reader.TopValidators(r.Context(), chain, page)
+		if err != nil {
+			s.internalError(w, err)
+			return
+		}
+		if stats == nil {
+			stats = []*alchemy.ValidatorStats{}
+		}
+		writeJSON(w, http.StatusOK, newPageResponse(stats, len(stats), page))
+	})
+}
+
+func (s *Server) internalError(w http.ResponseWriter, err error) {
+	s.logger.Errorf("api request failed: %v", err)
+	writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+}
+
+// pageParams разбирает limit и offset; cursor - только там, где выборка умеет keyset (withCursor).
+// Курсор и offset вместе не принимаются: offset отсчитывался бы уже от курсора.
+func pageParams(r *http.Request, withCursor bool) (alchemy.Page, error) {
+	page := alchemy.Page{Limit: defaultPageLimit}
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return page, fmt.Errorf("invalid limit: %q", v)
+		}
+		page.Limit = min(limit, maxPageLimit)
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("invalid offset: %q", v)
+		}
+		page.Offset = offset
+	}
+	if v := query.Get("cursor"); v != "" {
+		if !withCursor {
+			return page, errors.New("cursor is not supported here, use offset")
+		}
+		if query.Has("offset") {
+			return page, errors.New("cursor and offset are mutually exclusive")
+		}
+		cursor, err := decodeCursor(v)
+		if err != nil {
+			return page, err
+		}
+		page.After = cursor
+	}
+	return page, nil
+}
+
+// Курсор - "<unix секунды блока>_<номер блока>"
+func encodeCursor(c *alchemy.Cursor) string {
+	return fmt.Sprintf("%d_%d", c.BlockTime.Unix(), c.BlockNumber)
+}
+
+func decodeCursor(v string) (*alchemy.Cursor, error) {
+	ts, number, ok := strings.Cut(v, "_")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor: %q", v)
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %q", v)
+	}
+	n, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %q", v)
+	}
+	return &alchemy.Cursor{BlockTime: time.Unix(sec, 0), BlockNumber: n}, nil
+}
+
+func numberRangeParams(r *http.Request) (uint64, uint64, error) {
+	query := r.URL.Query()
+	from, err := strconv.ParseUint(query.Get("from"), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid from: %q", query.Get("from"))
+	}
+	to, err := strconv.ParseUint(query.Get("to"), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid to: %q", query.Get("to"))
+	}
+	if to < from {
+		return 0, 0, fmt.Errorf("to (%d) is less than from (%d)", to, from)
+	}
+	return from, to, nil
+}
+
+// timeRangeParams разбирает from_time/to_time (RFC3339 или unix секунды)
+// либо window относительно текущего момента. По умолчанию - последний час.
+func timeRangeParams(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	to := time.Now()
+
+	if v := query.Get("to_time"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to_time: %w", err)
+		}
+		to = t
+	}
+
+	if v := query.Get("from_time"); v != "" {
+		from, err := parseTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from_time: %w", err)
+		}
+		if !from.Before(to) {
+			return time.Time{}, time.Time{}, errors.New("from_time must be before to_time")
+		}
+		return from, to, nil
+	}
+
+	window := defaultWindow
+	if v := query.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid window: %q", v)
+		}
+		window = d
+	}
+	return to.Add(-window), to, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(ts, 0), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeReader - Reader с подменяемой выборкой блоков, остальные методы не вызываются
+type fakeReader struct {
+	alchemy.Reader
+	blocks []*alchemy.Block
+	err    error
+	pages  []alchemy.Page
+}
+
+func (f *fakeReader) BlocksByRange(_ context.Context, _ string, _, _ uint64, page alchemy.Page) ([]*alchemy.Block, error) {
+	f.pages = append(f.pages, page)
+	return f.blocks, f.err
+}
+
+func (f *fakeReader) BlocksByTime(_ context.Context, _ string, _, _ time.Time, page alchemy.Page) ([]*alchemy.Block, error) {
+	f.pages = append(f.pages, page)
+	return f.blocks, f.err
+}
+
+func testLogger() *logging.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return &logging.Logger{Entry: logrus.NewEntry(l)}
+}
+
+func newTestServer(reader alchemy.Reader) *Server {
+	s := NewServer(configs.ListenConfig{}, testLogger())
+	s.RegisterQueryAPI(reader)
+	return s
+}
+
+func get(t *testing.T, s *Server, url string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	return rec, body
+}
+
+func TestBlocksReaderError(t *testing.T) {
+	urls := map[string]string{
+		"by range": "/api/v1/chains/ethereum/blocks?from=1&to=10",
+		"by time":  "/api/v1/chains/ethereum/blocks?window=1h",
+	}
+	for name, url := range urls {
+		t.Run(name, func(t *testing.T) {
+			s := newTestServer(&fakeReader{err: errors.New("connection refused")})
+
+			rec, body := get(t, s, url)
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			if _, ok := body["items"]; ok {
+				t.Fatalf("error response must not contain items: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBlocksBadRange(t *testing.T) {
+	reader := &fakeReader{}
+	s := newTestServer(reader)
+
+	rec, _ := get(t, s, "/api/v1/chains/ethereum/blocks?from=10&to=1")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if len(reader.pages) != 0 {
+		t.Fatalf("reader called on invalid range")
+	}
+}
+
+func TestBlocksCursorPaging(t *testing.T) {
+	full := []*alchemy.Block{
+		{BlockNumber: 11, BlockTime: time.Unix(1_700_000_012, 0)},
+		{BlockNumber: 12, BlockTime: time.Unix(1_700_000_024, 0)},
+	}
+
+	t.Run("cursor with offset", func(t *testing.T) {
+		reader := &fakeReader{blocks: full}
+		rec, _ := get(t, newTestServer(reader), "/api/v1/chains/ethereum/blocks?from=1&to=100&limit=2&cursor=1700000000_10&offset=5")
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if len(reader.pages) != 0 {
+			t.Fatal("reader called with both cursor and offset")
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		reader := &fakeReader{blocks: full}
+		rec, body := get(t, newTestServer(reader), "/api/v1/chains/ethereum/blocks?from=1&to=100&limit=2&cursor=1700000000_10")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+		}
+		if page := reader.pages[0]; page.After == nil || page.After.BlockNumber != 10 || page.Offset != 0 {
+			t.Fatalf("unexpected page %+v", page)
+		}
+		if _, ok := body["next_offset"]; ok {
+			t.Fatalf("cursor page must not return next_offset: %s", rec.Body.String())
+		}
+		if got := string(body["next_cursor"]); got != `"1700000024_12"` {
+			t.Fatalf("next_cursor = %s", got)
+		}
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		reader := &fakeReader{blocks: full}
+		_, body := get(t, newTestServer(reader), "/api/v1/chains/ethereum/blocks?from=1&to=100&limit=2&offset=4")
+		if got := string(body["next_offset"]); got != "6" {
+			t.Fatalf("next_offset = %s, want 6", got)
+		}
+	})
+}
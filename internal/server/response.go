@@ -0,0 +1,34 @@
+package server
+
+import (
+	"blocks_gas_validators/pkg/chains"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// chainParam проверяет сеть по списку известных: имя сети попадает в имя таблицы
+func chainParam(r *http.Request) (string, error) {
+	chain := r.PathValue("chain")
+	if chain == "" {
+		chain = r.URL.Query().Get("chain")
+	}
+	if _, ok := chains.AlchemyChains[chain]; !ok {
+		return "", fmt.Errorf("unknown chain: %q", chain)
+	}
+	return chain, nil
+}
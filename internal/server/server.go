@@ -0,0 +1,110 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+type Server struct {
+	cfg    configs.ListenConfig
+	mux    *http.ServeMux
+	grpc   http.Handler
+	logger *logging.Logger
+}
+
+func NewServer(cfg configs.ListenConfig, logger *logging.Logger) *Server {
+	return &Server{
+		cfg:    cfg,
+		mux:    http.NewServeMux(),
+		logger: logger,
+	}
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// MountGRPC обслуживает gRPC на том же сокете, что и HTTP API (h2c)
+func (s *Server) MountGRPC(handler http.Handler) {
+	s.grpc = handler
+}
+
+func (s *Server) handler() http.Handler {
+	if s.grpc == nil {
+		return s.mux
+	}
+
+	mixed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			s.grpc.ServeHTTP(w, r)
+			return
+		}
+		s.mux.ServeHTTP(w, r)
+	})
+	return h2c.NewHandler(mixed, &http2.Server{})
+}
+
+// Listen открывает сокет согласно конфигу: tcp порт или unix сокет (type: sock)
+func Listen(cfg configs.ListenConfig) (net.Listener, error) {
+	if cfg.Type == "sock" {
+		// Сокет от предыдущего запуска мешает bind
+		if err := os.Remove(cfg.SocketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("remove stale socket %s: %w", cfg.SocketPath, err)
+		}
+		listener, err := net.Listen("unix", cfg.SocketPath)
+		if err != nil {
+			return nil, fmt.Errorf("listen unix socket %s: %w", cfg.SocketPath, err)
+		}
+		return listener, nil
+	}
+
+	addr := cfg.BindIP + cfg.Port
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s: %w", addr, err)
+	}
+	return listener, nil
+}
+
+// Run обслуживает запросы до отмены контекста
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := Listen(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			s.logger.Errorf("http server shutdown: %v", err)
+		}
+	}()
+
+	s.logger.Infof("http server listening on %s", listener.Addr())
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("http server: %w", err)
+	}
+	return nil
+}
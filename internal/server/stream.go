@@ -0,0 +1,201 @@
+package server
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/internal/stream"
+	"blocks_gas_validators/pkg/chains"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamBuffer - сколько блоков может ждать отправки одному клиенту
+	streamBuffer = 256
+	// maxDropped - после стольких потерянных блоков медленный клиент отключается
+	maxDropped   = 1000
+	writeTimeout = 10 * time.Second
+	pingInterval = 30 * time.Second
+)
+
+type blockEvent struct {
+	Chain string         `json:"chain"`
+	Block *alchemy.Block `json:"block"`
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	// Дашборды ходят с других origin, API только на чтение
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (s *Server) RegisterBlockStream(hub *stream.Hub) {
+	s.HandleFunc("GET /api/v1/stream/blocks/sse", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := streamFilter(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming unsupported"))
+			return
+		}
+
+		sub := hub.Subscribe(streamBuffer, filter)
+		defer hub.Unsubscribe(sub)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ping := time.NewTicker(pingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ping.C:
+				// Комментарий SSE не даёт прокси закрыть простаивающее соединение
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if sub.Dropped() > maxDropped {
+					s.logger.Warnf("sse client %s too slow, disconnecting", r.RemoteAddr)
+					return
+				}
+				data, err := json.Marshal(blockEvent{Chain: event.Chain, Block: event.Block})
+				if err != nil {
+					s.logger.Errorf("marshal block event: %v", err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: block\nid: %s-%d\ndata: %s\n\n", event.Chain, event.Block.BlockNumber, data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+
+	s.HandleFunc("GET /api/v1/stream/blocks/ws", func(w http.ResponseWriter, r *http.Request) {
+		filter, err := streamFilter(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.logger.Warnf("websocket upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		sub := hub.Subscribe(streamBuffer, filter)
+		defer hub.Unsubscribe(sub)
+
+		// Читаем входящие кадры только чтобы заметить закрытие со стороны клиента
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ping := time.NewTicker(pingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-r.Context().Done():
+				return
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+					return
+				}
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if sub.Dropped() > maxDropped {
+					s.logger.Warnf("websocket client %s too slow, disconnecting", r.RemoteAddr)
+					_ = conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "consumer too slow"),
+						time.Now().Add(writeTimeout))
+					return
+				}
+				_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+				if err := conn.WriteJSON(blockEvent{Chain: event.Chain, Block: event.Block}); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// streamFilter собирает фильтр из query: chain (через запятую),
+// min_fullness/max_fullness (%), min_gas_avg/max_gas_avg (gwei)
+func streamFilter(r *http.Request) (stream.Filter, error) {
+	query := r.URL.Query()
+
+	wanted := make(map[string]bool)
+	if v := query.Get("chain"); v != "" {
+		for _, chain := range strings.Split(v, ",") {
+			if _, ok := chains.AlchemyChains[chain]; !ok {
+				return nil, fmt.Errorf("unknown chain: %q", chain)
+			}
+			wanted[chain] = true
+		}
+	}
+
+	bounds := make(map[string]*float64)
+	for _, name := range []string{"min_fullness", "max_fullness", "min_gas_avg", "max_gas_avg"} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %q", name, v)
+		}
+		bounds[name] = &f
+	}
+
+	return func(chain string, block *alchemy.Block) bool {
+		if len(wanted) > 0 && !wanted[chain] {
+			return false
+		}
+		if b := bounds["min_fullness"]; b != nil && block.BlockFullness < *b {
+			return false
+		}
+		if b := bounds["max_fullness"]; b != nil && block.BlockFullness > *b {
+			return false
+		}
+		if b := bounds["min_gas_avg"]; b != nil && block.GasStats.Avg < *b {
+			return false
+		}
+		if b := bounds["max_gas_avg"]; b != nil && block.GasStats.Avg > *b {
+			return false
+		}
+		return true
+	}, nil
+}
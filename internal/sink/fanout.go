@@ -0,0 +1,194 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/metrics"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull = errors.New("sink queue full")
+	ErrClosed    = errors.New("sink closed")
+)
+
+// closeTimeout - сколько Close ждёт дописывания очереди, дальше запись и повторы прерываются
+const closeTimeout = 10 * time.Second
+
+type batch struct {
+	chain  string
+	blocks []*alchemy.Block
+}
+
+// buffered отделяет медленный или упавший sink от saver'а: Write только ставит пачку
+// в очередь, запись с повторами идёт в своей горутине. При переполнении пачка теряется.
+type buffered struct {
+	sink       alchemy.Sink
+	logger     *logging.Logger
+	retries    int
+	retryDelay time.Duration
+	closeWait  time.Duration
+
+	queue chan batch
+	done  chan struct{}
+	// mu и closed не дают Write отправить в уже закрытую очередь,
+	// если saver дописывает последнюю пачку параллельно с Close
+	mu     sync.Mutex
+	closed bool
+	// ctx живёт дольше контекста main, чтобы очередь дописалась при остановке,
+	// и отменяется в Close, если sink так и не ответил за closeWait
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewBuffered(sink alchemy.Sink, size, retries int, retryDelay time.Duration, logger *logging.Logger) alchemy.Sink {
+	if retries <= 0 {
+		retries = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &buffered{
+		sink:       sink,
+		logger:     logger,
+		retries:    retries,
+		retryDelay: retryDelay,
+		closeWait:  closeTimeout,
+		queue:      make(chan batch, size),
+		done:       make(chan struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	go b.run()
+	return b
+}
+
+func (b *buffered) Name() string {
+	return b.sink.Name()
+}
+
+func (b *buffered) Write(_ context.Context, chain string, blocks []*alchemy.Block) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		metrics.SinkDropped.WithLabelValues(chain, b.Name()).Add(float64(len(blocks)))
+		return ErrClosed
+	}
+
+	select {
+	case b.queue <- batch{chain: chain, blocks: blocks}:
+		metrics.SinkQueueDepth.WithLabelValues(b.Name()).Set(float64(len(b.queue)))
+		return nil
+	default:
+		metrics.SinkDropped.WithLabelValues(chain, b.Name()).Add(float64(len(blocks)))
+		return ErrQueueFull
+	}
+}
+
+func (b *buffered) run() {
+	defer close(b.done)
+
+	for item := range b.queue {
+		metrics.SinkQueueDepth.WithLabelValues(b.Name()).Set(float64(len(b.queue)))
+
+		err := b.write(item)
+		if err != nil {
+			b.logger.Errorf("sink %s dropped %d blocks after %d attempts: %v", b.Name(), len(item.blocks), b.retries, err)
+			metrics.SinkWrites.WithLabelValues(item.chain, b.Name(), "error").Inc()
+			metrics.SinkDropped.WithLabelValues(item.chain, b.Name()).Add(float64(len(item.blocks)))
+			continue
+		}
+		metrics.SinkWrites.WithLabelValues(item.chain, b.Name(), "ok").Inc()
+	}
+}
+
+// write пишет пачку с повторами; после отмены ctx остаток очереди сбрасывается без попыток
+func (b *buffered) write(item batch) error {
+	for attempt := 1; ; attempt++ {
+		if err := b.ctx.Err(); err != nil {
+			return err
+		}
+		err := b.sink.Write(b.ctx, item.chain, item.blocks)
+		if err == nil || attempt >= b.retries {
+			return err
+		}
+
+		select {
+		case <-b.ctx.Done():
+			return err
+		case <-time.After(b.retryDelay):
+		}
+	}
+}
+
+// Close дожидается записи того, что уже в очереди, но не дольше closeWait
+func (b *buffered) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(b.closeWait):
+		b.logger.Warnf("sink %s did not drain in %s, dropping the rest of the queue", b.Name(), b.closeWait)
+		b.cancel()
+		<-b.done
+	}
+	b.cancel()
+	return b.sink.Close()
+}
+
+// fanout пишет в основной sink синхронно, его ошибка - ошибка сохранения блока.
+// Остальные sink'и получают те же блоки независимо от результата основного.
+type fanout struct {
+	primary   alchemy.Sink
+	secondary []alchemy.Sink
+	logger    *logging.Logger
+}
+
+func NewFanout(primary alchemy.Sink, logger *logging.Logger, secondary ...alchemy.Sink) alchemy.Sink {
+	return &fanout{
+		primary:   primary,
+		secondary: secondary,
+		logger:    logger,
+	}
+}
+
+func (f *fanout) Name() string {
+	return "fanout"
+}
+
+func (f *fanout) Write(ctx context.Context, chain string, blocks []*alchemy.Block) error {
+	err := f.primary.Write(ctx, chain, blocks)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	metrics.SinkWrites.WithLabelValues(chain, f.primary.Name(), status).Inc()
+
+	for _, s := range f.secondary {
+		if serr := s.Write(ctx, chain, blocks); serr != nil {
+			f.logger.Warnf("sink %s skipped %d blocks: %v", s.Name(), len(blocks), serr)
+		}
+	}
+	return err
+}
+
+func (f *fanout) Close() error {
+	var errs []error
+	for _, s := range f.secondary {
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close sink %s: %w", s.Name(), err))
+		}
+	}
+	if err := f.primary.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close sink %s: %w", f.primary.Name(), err))
+	}
+	return errors.Join(errs...)
+}
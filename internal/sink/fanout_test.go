@@ -0,0 +1,246 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func testLogger() *logging.Logger {
+	l := logrus.New()
+	l.SetOutput(io.Discard)
+	return &logging.Logger{Entry: logrus.NewEntry(l)}
+}
+
+// fakeSink падает первые failures вызовов, потом пишет. gate, если задан,
+// держит каждый вызов, пока из него не прочитают; started сообщает о начале вызова.
+type fakeSink struct {
+	name     string
+	failures int
+	gate     chan struct{}
+	started  chan struct{}
+
+	mu      sync.Mutex
+	calls   int
+	written []uint64
+	closed  bool
+}
+
+func (f *fakeSink) Name() string {
+	return f.name
+}
+
+func (f *fakeSink) Write(ctx context.Context, _ string, blocks []*alchemy.Block) error {
+	if f.started != nil {
+		f.started <- struct{}{}
+	}
+	if f.gate != nil {
+		select {
+		case <-f.gate:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("sink unavailable")
+	}
+	for _, b := range blocks {
+		f.written = append(f.written, b.BlockNumber)
+	}
+	return nil
+}
+
+func (f *fakeSink) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSink) state() (int, []uint64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls, append([]uint64(nil), f.written...), f.closed
+}
+
+func blocks(numbers ...uint64) []*alchemy.Block {
+	out := make([]*alchemy.Block, 0, len(numbers))
+	for _, n := range numbers {
+		out = append(out, &alchemy.Block{BlockNumber: n})
+	}
+	return out
+}
+
+func TestBufferedRetriesUntilSuccess(t *testing.T) {
+	fake := &fakeSink{name: "fake", failures: 2}
+	b := NewBuffered(fake, 4, 3, time.Millisecond, testLogger())
+
+	if err := b.Write(context.Background(), "ethereum", blocks(1, 2)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	calls, written, closed := fake.state()
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+	if len(written) != 2 || !closed {
+		t.Fatalf("written %v, closed %t", written, closed)
+	}
+}
+
+func TestBufferedDropsAfterRetries(t *testing.T) {
+	fake := &fakeSink{name: "fake", failures: 2}
+	b := NewBuffered(fake, 4, 2, time.Millisecond, testLogger())
+
+	// Первая пачка исчерпывает повторы и теряется, вторая пишется
+	_ = b.Write(context.Background(), "ethereum", blocks(1))
+	_ = b.Write(context.Background(), "ethereum", blocks(2))
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if _, written, _ := fake.state(); len(written) != 1 || written[0] != 2 {
+		t.Fatalf("written %v, want [2]", written)
+	}
+}
+
+func TestBufferedQueueFull(t *testing.T) {
+	fake := &fakeSink{name: "fake", gate: make(chan struct{}), started: make(chan struct{}, 4)}
+	b := NewBuffered(fake, 1, 1, time.Millisecond, testLogger())
+
+	// Первая пачка уже в работе и держит воркер, вторая занимает очередь
+	if err := b.Write(context.Background(), "ethereum", blocks(1)); err != nil {
+		t.Fatalf("write 1: %v", err)
+	}
+	<-fake.started
+	if err := b.Write(context.Background(), "ethereum", blocks(2)); err != nil {
+		t.Fatalf("write 2: %v", err)
+	}
+	if err := b.Write(context.Background(), "ethereum", blocks(3)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("write 3 err = %v, want ErrQueueFull", err)
+	}
+
+	close(fake.gate)
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, written, _ := fake.state(); len(written) != 2 {
+		t.Fatalf("written %v, want [1 2]", written)
+	}
+}
+
+func TestBufferedCloseCancelsRetries(t *testing.T) {
+	fake := &fakeSink{name: "fake", failures: 1 << 30}
+	b := NewBuffered(fake, 4, 1000, time.Hour, testLogger())
+	b.(*buffered).closeWait = 50 * time.Millisecond
+
+	_ = b.Write(context.Background(), "ethereum", blocks(1))
+	_ = b.Write(context.Background(), "ethereum", blocks(2))
+
+	done := make(chan struct{})
+	go func() {
+		_ = b.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close blocked on retries of a dead sink")
+	}
+
+	calls, _, closed := fake.state()
+	if calls != 1 || !closed {
+		t.Fatalf("calls = %d, closed %t: queue must be dropped without new attempts", calls, closed)
+	}
+}
+
+func TestFanoutIsolatesSecondaryFailures(t *testing.T) {
+	primary := &fakeSink{name: "primary"}
+	failing := &fakeSink{name: "failing", failures: 1 << 30}
+	healthy := &fakeSink{name: "healthy"}
+
+	f := NewFanout(primary, testLogger(),
+		NewBuffered(failing, 4, 1, time.Millisecond, testLogger()),
+		NewBuffered(healthy, 4, 1, time.Millisecond, testLogger()),
+	)
+	if err := f.Write(context.Background(), "ethereum", blocks(1, 2)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	for _, s := range []*fakeSink{primary, healthy} {
+		if _, written, closed := s.state(); len(written) != 2 || !closed {
+			t.Fatalf("sink %s written %v, closed %t", s.name, written, closed)
+		}
+	}
+	if calls, _, closed := failing.state(); calls != 1 || !closed {
+		t.Fatalf("failing sink calls = %d, closed %t", calls, closed)
+	}
+}
+
+func TestFanoutReturnsPrimaryError(t *testing.T) {
+	primary := &fakeSink{name: "primary", failures: 1}
+	secondary := &fakeSink{name: "secondary"}
+
+	f := NewFanout(primary, testLogger(), secondary)
+	if err := f.Write(context.Background(), "ethereum", blocks(1)); err == nil {
+		t.Fatal("primary error must be returned")
+	}
+	if _, written, _ := secondary.state(); len(written) != 1 {
+		t.Fatalf("secondary written %v, want [1]", written)
+	}
+}
+
+func TestBufferedWriteAfterClose(t *testing.T) {
+	fake := &fakeSink{name: "fake"}
+	b := NewBuffered(fake, 4, 1, time.Millisecond, testLogger())
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if err := b.Write(context.Background(), "ethereum", blocks(1)); !errors.Is(err, ErrClosed) {
+		t.Fatalf("write after close err = %v, want ErrClosed", err)
+	}
+	if _, written, _ := fake.state(); len(written) != 0 {
+		t.Fatalf("written %v after close", written)
+	}
+}
+
+// Последняя пачка saver'а может прийти одновременно с остановкой
+func TestBufferedWriteRacesClose(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		b := NewBuffered(&fakeSink{name: "fake"}, 4, 1, time.Millisecond, testLogger())
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for n := uint64(0); n < 20; n++ {
+				if err := b.Write(context.Background(), "ethereum", blocks(n)); err != nil && !errors.Is(err, ErrClosed) && !errors.Is(err, ErrQueueFull) {
+					t.Errorf("write: %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_ = b.Close()
+		}()
+		wg.Wait()
+	}
+}
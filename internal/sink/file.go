@@ -0,0 +1,111 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// jsonlSink дописывает блоки в <dir>/<chain>_<день UTC>.jsonl в формате,
+// который понимает mode: import. Не потокобезопасен, оборачивается в Buffered.
+type jsonlSink struct {
+	dir string
+
+	file *os.File
+	buf  *bufio.Writer
+	path string
+}
+
+func NewJSONLSink(dir string) (alchemy.Sink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create sink dir: %w", err)
+	}
+	return &jsonlSink{dir: dir}, nil
+}
+
+func (j *jsonlSink) Name() string {
+	return "jsonl"
+}
+
+func (j *jsonlSink) Write(_ context.Context, chain string, blocks []*alchemy.Block) error {
+	for _, block := range blocks {
+		path := filepath.Join(j.dir, fmt.Sprintf("%s_%s.jsonl", chain, block.BlockTime.UTC().Format("2006-01-02")))
+		if path != j.path {
+			if err := j.open(path); err != nil {
+				return err
+			}
+		}
+
+		if err := writeJSONLine(j.buf, block); err != nil {
+			return err
+		}
+	}
+	return j.buf.Flush()
+}
+
+func (j *jsonlSink) open(path string) error {
+	if err := j.Close(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open sink file: %w", err)
+	}
+	j.file, j.buf, j.path = file, bufio.NewWriter(file), path
+	return nil
+}
+
+func (j *jsonlSink) Close() error {
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.buf.Flush()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file, j.buf, j.path = nil, nil, ""
+	return err
+}
+
+// stdoutSink печатает блоки построчно в JSON, удобно для пайпов в jq и отладки
+type stdoutSink struct {
+	out *bufio.Writer
+}
+
+func NewStdoutSink() alchemy.Sink {
+	return &stdoutSink{out: bufio.NewWriter(os.Stdout)}
+}
+
+func (s *stdoutSink) Name() string {
+	return "stdout"
+}
+
+func (s *stdoutSink) Write(_ context.Context, _ string, blocks []*alchemy.Block) error {
+	for _, block := range blocks {
+		if err := writeJSONLine(s.out, block); err != nil {
+			return err
+		}
+	}
+	return s.out.Flush()
+}
+
+func (s *stdoutSink) Close() error {
+	return s.out.Flush()
+}
+
+func writeJSONLine(w io.Writer, block *alchemy.Block) error {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("marshal block %d: %w", block.BlockNumber, err)
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
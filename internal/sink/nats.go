@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const natsFlushTimeout = 5 * time.Second
+
+// natsSink публикует каждый блок отдельным сообщением в <subject>.<chain>.blocks
+type natsSink struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func NewNATSSink(url, subject string) (alchemy.Sink, error) {
+	conn, err := nats.Connect(url, nats.Name("blocks-miner"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats %s: %w", url, err)
+	}
+	return &natsSink{conn: conn, subject: subject}, nil
+}
+
+func (n *natsSink) Name() string {
+	return "nats"
+}
+
+func (n *natsSink) Write(ctx context.Context, chain string, blocks []*alchemy.Block) error {
+	subject := fmt.Sprintf("%s.%s.blocks", n.subject, chain)
+	for _, block := range blocks {
+		data, err := json.Marshal(block)
+		if err != nil {
+			return fmt.Errorf("marshal block %d: %w", block.BlockNumber, err)
+		}
+		if err := n.conn.Publish(subject, data); err != nil {
+			return fmt.Errorf("publish block %d: %w", block.BlockNumber, err)
+		}
+	}
+
+	// Publish только кладёт в буфер клиента, Flush ждёт подтверждения сервера
+	flushCtx, cancel := context.WithTimeout(ctx, natsFlushTimeout)
+	defer cancel()
+	if err := n.conn.FlushWithContext(flushCtx); err != nil {
+		return fmt.Errorf("flush nats: %w", err)
+	}
+	return nil
+}
+
+func (n *natsSink) Close() error {
+	return n.conn.Drain()
+}
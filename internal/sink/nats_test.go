@@ -0,0 +1,83 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+func TestNATSSinkPublishesBlocks(t *testing.T) {
+	srv := natsserver.RunRandClientPortServer()
+	defer srv.Shutdown()
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close()
+	msgs := make(chan *nats.Msg, 8)
+	if _, err := conn.ChanSubscribe("miner.ethereum.blocks", msgs); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatalf("flush subscription: %v", err)
+	}
+
+	s, err := NewNATSSink(srv.ClientURL(), "miner")
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	if err := s.Write(context.Background(), "ethereum", blocks(10, 11)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	for _, want := range []uint64{10, 11} {
+		select {
+		case msg := <-msgs:
+			var block alchemy.Block
+			if err := json.Unmarshal(msg.Data, &block); err != nil {
+				t.Fatalf("decode message: %v", err)
+			}
+			if block.BlockNumber != want {
+				t.Fatalf("block %d, want %d", block.BlockNumber, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d not published", want)
+		}
+	}
+}
+
+// Упавший брокер не должен ни ломать запись основного sink'а, ни держать остановку
+func TestNATSSinkBrokerDown(t *testing.T) {
+	srv := natsserver.RunRandClientPortServer()
+	s, err := NewNATSSink(srv.ClientURL(), "miner")
+	if err != nil {
+		t.Fatalf("new sink: %v", err)
+	}
+	srv.Shutdown()
+
+	primary := &fakeSink{name: "primary"}
+	b := NewBuffered(s, 4, 3, time.Second, testLogger())
+	b.(*buffered).closeWait = 100 * time.Millisecond
+	f := NewFanout(primary, testLogger(), b)
+
+	started := time.Now()
+	if err := f.Write(context.Background(), "ethereum", blocks(1)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_ = f.Close()
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Fatalf("write and close took %s with the broker down", elapsed)
+	}
+	if _, written, _ := primary.state(); len(written) != 1 {
+		t.Fatalf("primary written %v, want [1]", written)
+	}
+}
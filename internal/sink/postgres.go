@@ -0,0 +1,39 @@
+package sink
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"context"
+)
+
+// copyThreshold - с какого размера пачки COPY выгоднее batch insert
+const copyThreshold = 999
+
+type postgresSink struct {
+	db alchemy.Storage
+}
+
+func NewPostgresSink(db alchemy.Storage) alchemy.Sink {
+	return &postgresSink{db: db}
+}
+
+func (p *postgresSink) Name() string {
+	return "postgres"
+}
+
+func (p *postgresSink) Write(ctx context.Context, chain string, blocks []*alchemy.Block) error {
+	switch {
+	case len(blocks) == 0:
+		return nil
+	case len(blocks) == 1:
+		return p.db.Create(ctx, blocks[0], chain)
+	case len(blocks) < copyThreshold:
+		return p.db.InsertBlocksBatch(ctx, blocks, chain)
+	default:
+		return p.db.InsertBlocksCopy(ctx, blocks, chain)
+	}
+}
+
+// Close ничего не делает: пулом соединений владеет main
+func (p *postgresSink) Close() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type Event struct {
+	Chain string
+	Block *alchemy.Block
+}
+
+// Filter решает, нужен ли подписчику блок
+type Filter func(chain string, block *alchemy.Block) bool
+
+type Subscription struct {
+	C <-chan Event
+
+	ch      chan Event
+	filter  Filter
+	dropped atomic.Int64
+}
+
+// Dropped - сколько событий не влезло в буфер медленного подписчика
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Hub раздаёт сохранённые блоки подписчикам. Публикация никогда не блокируется:
+// если буфер подписчика полон, событие для него отбрасывается.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	logger *logging.Logger
+}
+
+func NewHub(logger *logging.Logger) *Hub {
+	return &Hub{
+		subs:   make(map[*Subscription]struct{}),
+		logger: logger,
+	}
+}
+
+func (h *Hub) Subscribe(buffer int, filter Filter) *Subscription {
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (h *Hub) OnBlocksSaved(_ context.Context, chain string, blocks []*alchemy.Block) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		for _, block := range blocks {
+			if sub.filter != nil && !sub.filter(chain, block) {
+				continue
+			}
+			select {
+			case sub.ch <- Event{Chain: chain, Block: block}:
+			default:
+				if sub.dropped.Add(1)%100 == 1 {
+					h.logger.Warnf("slow stream subscriber, dropped %d blocks so far", sub.dropped.Load())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package verify
+
+import (
+	"blocks_gas_validators/internal/configs"
+	"blocks_gas_validators/internal/miner/alchemy"
+	"blocks_gas_validators/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	CompareNone   = "none"
+	CompareSample = "sample"
+	CompareAll    = "all"
+)
+
+// windowSize - сколько номеров проверяется за один проход, ограничивает память под сверку
+const windowSize = 1000
+
+type Issue struct {
+	BlockNumber uint64 `json:"block_number"`
+	Detail      string `json:"detail"`
+}
+
+type Report struct {
+	Chain      string               `json:"chain"`
+	From       uint64               `json:"from"`
+	To         uint64               `json:"to"`
+	Scanned    int                  `json:"scanned"`
+	Missing    uint64               `json:"missing"`
+	Gaps       []alchemy.BlockRange `json:"gaps"`
+	Duplicates []uint64             `json:"duplicates"`
+	Suspicious []Issue              `json:"suspicious"`
+	Compared   int                  `json:"compared"`
+	Mismatches []Issue              `json:"mismatches"`
+	Repaired   int                  `json:"repaired"`
+	Failed     []Issue              `json:"failed"`
+}
+
+func (r *Report) OK() bool {
+	return len(r.Gaps) == 0 && len(r.Duplicates) == 0 && len(r.Suspicious) == 0 && len(r.Mismatches) == 0
+}
+
+// Verifier проверяет сохранённые блоки сети: пропуски, дубли одного номера, невозможные
+// значения и, по настройке, расхождения с RPC. Починка - перезапрос блока и запись
+// через тот же Worker, что и у history: sink'и, метрики и listeners.
+type Verifier struct {
+	Reader    alchemy.Reader
+	DB        alchemy.Storage
+	Saver     alchemy.Worker
+	Collector alchemy.Collector
+	Logger    *logging.Logger
+	Chain     string
+	Cfg       configs.VerifyConfig
+	Workers   int
+
+	limiter *rate.Limiter
+}
+
+func NewVerifier(reader alchemy.Reader, db alchemy.Storage, saver alchemy.Worker, collector alchemy.Collector, chain string, cfg configs.VerifyConfig, workers, limit int, logger *logging.Logger) (*Verifier, error) {
+	switch cfg.Compare {
+	case CompareNone, CompareSample, CompareAll:
+	default:
+		return nil, fmt.Errorf("unknown verify compare mode: %s", cfg.Compare)
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return &Verifier{
+		Reader:    reader,
+		DB:        db,
+		Saver:     saver,
+		Collector: collector,
+		Logger:    logger,
+		Chain:     chain,
+		Cfg:       cfg,
+		Workers:   workers,
+		limiter:   rate.NewLimiter(rate.Limit(limit), 10),
+	}, nil
+}
+
+func (v *Verifier) Run(ctx context.Context, from, to uint64) (*Report, error) {
+	if to == 0 {
+		latest, err := v.Reader.LatestBlock(ctx, v.Chain)
+		if err != nil {
+			return nil, fmt.Errorf("find latest stored block: %w", err)
+		}
+		to = latest.BlockNumber
+	}
+	if to < from {
+		return nil, fmt.Errorf("verify range end %d is before start %d", to, from)
+	}
+
+	report := &Report{Chain: v.Chain, From: from, To: to}
+
+	gaps, err := v.Reader.MissingRanges(ctx, v.Chain, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report.Gaps = gaps
+	for _, gap := range gaps {
+		report.Missing += gap.Len()
+	}
+
+	// Номера, которые надо перезапросить, и уже полученные из RPC блоки
+	repair := make(map[uint64]*alchemy.Block)
+
+	for start := from; start <= to; start += windowSize {
+		end := min(start+windowSize-1, to)
+		if err := v.scanWindow(ctx, start, end, report, repair); err != nil {
+			return nil, err
+		}
+		// start+windowSize может переполниться только на последнем окне
+		if end == to {
+			break
+		}
+	}
+
+	if v.Cfg.Repair {
+		for _, gap := range gaps {
+			for n := gap.From; n <= gap.To; n++ {
+				repair[n] = nil
+			}
+		}
+		// У сохранённых блоков могла быть копия с неверным block_time, её upsert не заменит
+		stale := make(map[uint64]bool)
+		for _, n := range report.Duplicates {
+			stale[n] = true
+		}
+		for _, issue := range report.Suspicious {
+			stale[issue.BlockNumber] = true
+		}
+		for _, issue := range report.Mismatches {
+			stale[issue.BlockNumber] = true
+		}
+		v.repair(ctx, repair, stale, report)
+	}
+
+	v.log(report)
+	if v.Cfg.Report != "" {
+		if err := writeReport(v.Cfg.Report, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// scanWindow проверяет блоки [from, to]: дубли и невозможные значения по базе,
+// затем сверяет выбранные блоки с RPC
+func (v *Verifier) scanWindow(ctx context.Context, from, to uint64, report *Report, repair map[uint64]*alchemy.Block) error {
+	var (
+		prev    *alchemy.Block
+		compare []*alchemy.Block
+	)
+
+	err := v.Reader.StreamBlocksByRange(ctx, v.Chain, from, to, func(block *alchemy.Block) error {
+		report.Scanned++
+
+		if prev != nil && prev.BlockNumber == block.BlockNumber {
+			if len(report.Duplicates) == 0 || report.Duplicates[len(report.Duplicates)-1] != block.BlockNumber {
+				report.Duplicates = append(report.Duplicates, block.BlockNumber)
+				repair[block.BlockNumber] = nil
+			}
+		}
+		prev = block
+
+		if problems := Check(block); len(problems) > 0 {
+			report.Suspicious = append(report.Suspicious, Issue{BlockNumber: block.BlockNumber, Detail: strings.Join(problems, "; ")})
+			repair[block.BlockNumber] = nil
+		}
+
+		if v.Cfg.Compare == CompareAll || (v.Cfg.Compare == CompareSample && rand.Float64() < v.Cfg.SampleRate) {
+			compare = append(compare, block)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scan blocks [%d, %d]: %w", from, to, err)
+	}
+
+	numbers := make([]uint64, len(compare))
+	for i, block := range compare {
+		numbers[i] = block.BlockNumber
+	}
+	fetched := v.fetch(ctx, numbers, report)
+	for _, stored := range compare {
+		remote, ok := fetched[stored.BlockNumber]
+		if !ok {
+			continue
+		}
+		report.Compared++
+		if diff := Compare(stored, remote); len(diff) > 0 {
+			report.Mismatches = append(report.Mismatches, Issue{BlockNumber: stored.BlockNumber, Detail: strings.Join(diff, "; ")})
+			repair[stored.BlockNumber] = remote
+		}
+	}
+	return nil
+}
+
+// fetch параллельно запрашивает блоки из RPC, ошибки попадают в report.Failed
+func (v *Verifier) fetch(ctx context.Context, numbers []uint64, report *Report) map[uint64]*alchemy.Block {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		fetched = make(map[uint64]*alchemy.Block, len(numbers))
+		queue   = make(chan uint64)
+	)
+
+	for i := 0; i < v.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				if err := v.limiter.Wait(ctx); err != nil {
+					return
+				}
+				block, err := v.Collector.CollectBlockByNumber(ctx, n)
+
+				mu.Lock()
+				if err != nil {
+					report.Failed = append(report.Failed, Issue{BlockNumber: n, Detail: err.Error()})
+				} else {
+					fetched[n] = block
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for _, n := range numbers {
+		select {
+		case <-ctx.Done():
+			break send
+		case queue <- n:
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return fetched
+}
+
+// repair перезапрашивает блоки без готового ответа RPC и пишет все пачками через Saver.
+// Для stale блоков копии с другим block_time удаляются после записи.
+func (v *Verifier) repair(ctx context.Context, blocks map[uint64]*alchemy.Block, stale map[uint64]bool, report *Report) {
+	numbers := make([]uint64, 0, len(blocks))
+	for n := range blocks {
+		numbers = append(numbers, n)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	v.Logger.Infof("repairing %d blocks of %s", len(numbers), v.Chain)
+
+	for start := 0; start < len(numbers); start += windowSize {
+		if ctx.Err() != nil {
+			return
+		}
+		chunk := numbers[start:min(start+windowSize, len(numbers))]
+
+		var missing []uint64
+		for _, n := range chunk {
+			if blocks[n] == nil {
+				missing = append(missing, n)
+			}
+		}
+		for n, block := range v.fetch(ctx, missing, report) {
+			blocks[n] = block
+		}
+
+		var batch []*alchemy.Block
+		for _, n := range chunk {
+			if blocks[n] != nil {
+				batch = append(batch, blocks[n])
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		if err := v.Saver.SaveBatch(ctx, batch); err != nil {
+			v.Logger.Errorf("failed to save repaired blocks: %v", err)
+			for _, block := range batch {
+				report.Failed = append(report.Failed, Issue{BlockNumber: block.BlockNumber, Detail: err.Error()})
+			}
+			continue
+		}
+
+		for _, block := range batch {
+			if !stale[block.BlockNumber] {
+				continue
+			}
+			if err := v.DB.DeleteBlockDuplicates(ctx, v.Chain, block.BlockNumber, block.BlockTime); err != nil {
+				report.Failed = append(report.Failed, Issue{BlockNumber: block.BlockNumber, Detail: err.Error()})
+			}
+		}
+
+		report.Repaired += len(batch)
+	}
+}
+
+// Check возвращает невозможные для сохранённого блока значения
+func Check(block *alchemy.Block) []string {
+	var problems []string
+	if block.BlockFullness < 0 || block.BlockFullness > 100 {
+		problems = append(problems, fmt.Sprintf("block_fullness %.2f out of [0, 100]", block.BlockFullness))
+	}
+	if block.BlockTimestamp == 0 {
+		problems = append(problems, "zero block_timestamp")
+	} else if block.BlockTime.Unix() != int64(block.BlockTimestamp) {
+		problems = append(problems, fmt.Sprintf("block_time %s does not match block_timestamp %d", block.BlockTime, block.BlockTimestamp))
+	}
+	if block.GasLimit == 0 {
+		problems = append(problems, "zero gas_limit")
+	}
+	if block.GasUsed > block.GasLimit {
+		problems = append(problems, fmt.Sprintf("gas_used %d exceeds gas_limit %d", block.GasUsed, block.GasLimit))
+	}
+	if block.GasStats.Min > block.GasStats.Max {
+		problems = append(problems, fmt.Sprintf("gas_min %.4f above gas_max %.4f", block.GasStats.Min, block.GasStats.Max))
+	}
+	if block.GasStats.Min < 0 || block.BaseFee < 0 {
+		problems = append(problems, "negative gas price")
+	}
+	if block.UnknownTxCount > block.TransactionsCount {
+		problems = append(problems, fmt.Sprintf("unknown_tx_count %d exceeds transactions_count %d", block.UnknownTxCount, block.TransactionsCount))
+	}
+	if len(block.GasStats.AllPrices) > block.TransactionsCount {
+		problems = append(problems, fmt.Sprintf("%d gas prices for %d transactions", len(block.GasStats.AllPrices), block.TransactionsCount))
+	}
+	if block.Validator == "" {
+		problems = append(problems, "empty block_author")
+	}
+	return problems
+}
+
+// Compare сравнивает сохранённый блок с полученным из RPC. Цены сравниваются
+// с допуском: в базе они хранятся double precision после деления на 1e9.
+func Compare(stored, remote *alchemy.Block) []string {
+	var diff []string
+	uints := []struct {
+		name          string
+		stored, fresh uint64
+	}{
+		{"block_timestamp", stored.BlockTimestamp, remote.BlockTimestamp},
+		{"gas_limit", stored.GasLimit, remote.GasLimit},
+		{"gas_used", stored.GasUsed, remote.GasUsed},
+		{"block_size_bytes", stored.BlockSizeBytes, remote.BlockSizeBytes},
+		{"transactions_count", uint64(stored.TransactionsCount), uint64(remote.TransactionsCount)},
+		{"unknown_tx_count", uint64(stored.UnknownTxCount), uint64(remote.UnknownTxCount)},
+	}
+	for _, f := range uints {
+		if f.stored != f.fresh {
+			diff = append(diff, fmt.Sprintf("%s: stored %d, rpc %d", f.name, f.stored, f.fresh))
+		}
+	}
+
+	floats := []struct {
+		name          string
+		stored, fresh float64
+	}{
+		{"block_fullness", stored.BlockFullness, remote.BlockFullness},
+		{"base_fee", stored.BaseFee, remote.BaseFee},
+		{"gas_min", stored.GasStats.Min, remote.GasStats.Min},
+		{"gas_max", stored.GasStats.Max, remote.GasStats.Max},
+		{"gas_avg", stored.GasStats.Avg, remote.GasStats.Avg},
+	}
+	for _, f := range floats {
+		if !almostEqual(f.stored, f.fresh) {
+			diff = append(diff, fmt.Sprintf("%s: stored %g, rpc %g", f.name, f.stored, f.fresh))
+		}
+	}
+
+	if !strings.EqualFold(stored.Validator, remote.Validator) {
+		diff = append(diff, fmt.Sprintf("block_author: stored %s, rpc %s", stored.Validator, remote.Validator))
+	}
+	if !stored.BlockTime.Equal(remote.BlockTime) {
+		diff = append(diff, fmt.Sprintf("block_time: stored %s, rpc %s", stored.BlockTime, remote.BlockTime))
+	}
+	return diff
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func (v *Verifier) log(report *Report) {
+	for _, gap := range report.Gaps {
+		v.Logger.Warnf("gap: blocks %d-%d (%d) missing", gap.From, gap.To, gap.Len())
+	}
+	for _, n := range report.Duplicates {
+		v.Logger.Warnf("duplicate: block %d stored more than once", n)
+	}
+	for _, issue := range report.Suspicious {
+		v.Logger.Warnf("suspicious: block %d: %s", issue.BlockNumber, issue.Detail)
+	}
+	for _, issue := range report.Mismatches {
+		v.Logger.Warnf("mismatch: block %d: %s", issue.BlockNumber, issue.Detail)
+	}
+	for _, issue := range report.Failed {
+		v.Logger.Errorf("failed: block %d: %s", issue.BlockNumber, issue.Detail)
+	}
+
+	v.Logger.Infof(
+		"verified %s [%d, %d]: scanned %d, missing %d in %d gaps, duplicates %d, suspicious %d, compared %d, mismatches %d, repaired %d, failed %d",
+		report.Chain, report.From, report.To, report.Scanned, report.Missing, len(report.Gaps), len(report.Duplicates),
+		len(report.Suspicious), report.Compared, len(report.Mismatches), report.Repaired, len(report.Failed),
+	)
+}
+
+func writeReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode verify report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write verify report %s: %w", path, err)
+	}
+	return nil
+}
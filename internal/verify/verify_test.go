@@ -0,0 +1,107 @@
+package verify
+
+import (
+	"blocks_gas_validators/internal/miner/alchemy"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validBlock - блок, в котором Check не находит проблем
+func validBlock() *alchemy.Block {
+	return &alchemy.Block{
+		BlockNumber:       100,
+		BlockTime:         time.Unix(1_700_000_000, 0),
+		BlockTimestamp:    1_700_000_000,
+		TransactionsCount: 3,
+		UnknownTxCount:    1,
+		BlockSizeBytes:    2048,
+		GasLimit:          30_000_000,
+		GasUsed:           15_000_000,
+		BlockFullness:     50,
+		BaseFee:           12.5,
+		Validator:         "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5",
+		GasStats:          alchemy.GasStats{Min: 13, Max: 40, Avg: 21.5, AllPrices: []float64{13, 21.5, 40}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(b *alchemy.Block)
+		want   []string
+	}{
+		{name: "valid", modify: func(*alchemy.Block) {}},
+		{name: "fullness above 100", modify: func(b *alchemy.Block) { b.BlockFullness = 100.5 }, want: []string{"block_fullness"}},
+		{name: "negative fullness", modify: func(b *alchemy.Block) { b.BlockFullness = -1 }, want: []string{"block_fullness"}},
+		{name: "zero timestamp", modify: func(b *alchemy.Block) { b.BlockTimestamp = 0 }, want: []string{"zero block_timestamp"}},
+		{name: "time does not match timestamp", modify: func(b *alchemy.Block) { b.BlockTime = b.BlockTime.Add(time.Hour) }, want: []string{"does not match block_timestamp"}},
+		{name: "zero gas limit", modify: func(b *alchemy.Block) { b.GasLimit, b.GasUsed = 0, 0 }, want: []string{"zero gas_limit"}},
+		{name: "gas used above limit", modify: func(b *alchemy.Block) { b.GasUsed = b.GasLimit + 1 }, want: []string{"exceeds gas_limit"}},
+		{name: "min above max", modify: func(b *alchemy.Block) { b.GasStats.Min = 50 }, want: []string{"gas_min 50.0000 above gas_max"}},
+		{name: "negative price", modify: func(b *alchemy.Block) { b.BaseFee = -1 }, want: []string{"negative gas price"}},
+		{name: "unknown above total", modify: func(b *alchemy.Block) { b.UnknownTxCount = 4 }, want: []string{"unknown_tx_count 4 exceeds"}},
+		{name: "more prices than transactions", modify: func(b *alchemy.Block) { b.TransactionsCount, b.UnknownTxCount = 2, 0 }, want: []string{"3 gas prices for 2 transactions"}},
+		{name: "empty author", modify: func(b *alchemy.Block) { b.Validator = "" }, want: []string{"empty block_author"}},
+		{
+			name:   "several problems",
+			modify: func(b *alchemy.Block) { b.GasLimit, b.Validator = 0, "" },
+			want:   []string{"zero gas_limit", "exceeds gas_limit", "empty block_author"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			block := validBlock()
+			tc.modify(block)
+
+			got := Check(block)
+			if len(got) != len(tc.want) {
+				t.Fatalf("problems %q, want %d matching %q", got, len(tc.want), tc.want)
+			}
+			for i := range got {
+				if !strings.Contains(got[i], tc.want[i]) {
+					t.Fatalf("problem %q does not mention %q", got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(remote *alchemy.Block)
+		want   []string
+	}{
+		{name: "equal", modify: func(*alchemy.Block) {}},
+		{name: "author case differs", modify: func(r *alchemy.Block) { r.Validator = strings.ToUpper(r.Validator) }},
+		{name: "price within double precision", modify: func(r *alchemy.Block) { r.GasStats.Avg += 1e-12 }},
+		{name: "gas used", modify: func(r *alchemy.Block) { r.GasUsed++ }, want: []string{"gas_used: stored 15000000, rpc 15000001"}},
+		{name: "transactions count", modify: func(r *alchemy.Block) { r.TransactionsCount = 4 }, want: []string{"transactions_count: stored 3, rpc 4"}},
+		{name: "price", modify: func(r *alchemy.Block) { r.GasStats.Avg = 22 }, want: []string{"gas_avg: stored 21.5, rpc 22"}},
+		{name: "base fee", modify: func(r *alchemy.Block) { r.BaseFee = 12.6 }, want: []string{"base_fee"}},
+		{name: "author", modify: func(r *alchemy.Block) { r.Validator = "0x1" }, want: []string{"block_author"}},
+		{name: "block time", modify: func(r *alchemy.Block) { r.BlockTime = r.BlockTime.Add(time.Second) }, want: []string{"block_time"}},
+		{
+			name:   "several fields",
+			modify: func(r *alchemy.Block) { r.GasLimit, r.BlockFullness = 1, 99 },
+			want:   []string{"gas_limit", "block_fullness"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stored, remote := validBlock(), validBlock()
+			tc.modify(remote)
+
+			got := Compare(stored, remote)
+			if len(got) != len(tc.want) {
+				t.Fatalf("diff %q, want %d matching %q", got, len(tc.want), tc.want)
+			}
+			for i := range got {
+				if !strings.Contains(got[i], tc.want[i]) {
+					t.Fatalf("diff %q does not mention %q", got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
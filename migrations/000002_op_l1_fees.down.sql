@@ -0,0 +1,47 @@
+ALTER TABLE ethereum_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
+
+ALTER TABLE polygon_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
+
+ALTER TABLE avalanche_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
+
+ALTER TABLE bnb_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
+
+ALTER TABLE base_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
+
+ALTER TABLE optimism_block_metrics
+    DROP COLUMN IF EXISTS l1_fee_total,
+    DROP COLUMN IF EXISTS l1_fee_avg,
+    DROP COLUMN IF EXISTS l1_gas_used,
+    DROP COLUMN IF EXISTS l1_base_fee_scalar,
+    DROP COLUMN IF EXISTS l1_blob_base_fee_scalar,
+    DROP COLUMN IF EXISTS deposit_tx_count;
@@ -0,0 +1,47 @@
+ALTER TABLE ethereum_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
+
+ALTER TABLE polygon_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
+
+ALTER TABLE avalanche_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
+
+ALTER TABLE bnb_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
+
+ALTER TABLE base_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
+
+ALTER TABLE optimism_block_metrics
+    ADD COLUMN IF NOT EXISTS l1_fee_total DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_fee_avg DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS l1_gas_used BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar BIGINT,
+    ADD COLUMN IF NOT EXISTS deposit_tx_count INT;
@@ -0,0 +1,11 @@
+ALTER TABLE ethereum_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
+
+ALTER TABLE polygon_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
+
+ALTER TABLE avalanche_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
+
+ALTER TABLE bnb_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
+
+ALTER TABLE base_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
+
+ALTER TABLE optimism_block_metrics DROP COLUMN IF EXISTS unknown_tx_count;
@@ -0,0 +1,11 @@
+ALTER TABLE ethereum_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE polygon_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE avalanche_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE bnb_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE base_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE optimism_block_metrics ADD COLUMN IF NOT EXISTS unknown_tx_count INT NOT NULL DEFAULT 0;
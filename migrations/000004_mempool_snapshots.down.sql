@@ -0,0 +1,11 @@
+DROP TABLE ethereum_mempool_snapshots;
+
+DROP TABLE polygon_mempool_snapshots;
+
+DROP TABLE avalanche_mempool_snapshots;
+
+DROP TABLE bnb_mempool_snapshots;
+
+DROP TABLE base_mempool_snapshots;
+
+DROP TABLE optimism_mempool_snapshots;
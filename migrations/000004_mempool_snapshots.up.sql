@@ -0,0 +1,95 @@
+CREATE TABLE IF NOT EXISTS ethereum_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS polygon_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS avalanche_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS bnb_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS base_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS optimism_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
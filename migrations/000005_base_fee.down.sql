@@ -0,0 +1,11 @@
+ALTER TABLE ethereum_block_metrics DROP COLUMN IF EXISTS base_fee;
+
+ALTER TABLE polygon_block_metrics DROP COLUMN IF EXISTS base_fee;
+
+ALTER TABLE avalanche_block_metrics DROP COLUMN IF EXISTS base_fee;
+
+ALTER TABLE bnb_block_metrics DROP COLUMN IF EXISTS base_fee;
+
+ALTER TABLE base_block_metrics DROP COLUMN IF EXISTS base_fee;
+
+ALTER TABLE optimism_block_metrics DROP COLUMN IF EXISTS base_fee;
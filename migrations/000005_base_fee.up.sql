@@ -0,0 +1,11 @@
+ALTER TABLE ethereum_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
+
+ALTER TABLE polygon_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
+
+ALTER TABLE avalanche_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
+
+ALTER TABLE bnb_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
+
+ALTER TABLE base_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
+
+ALTER TABLE optimism_block_metrics ADD COLUMN IF NOT EXISTS base_fee DOUBLE PRECISION;
@@ -0,0 +1,11 @@
+DROP TABLE ethereum_gas_rollups;
+
+DROP TABLE polygon_gas_rollups;
+
+DROP TABLE avalanche_gas_rollups;
+
+DROP TABLE bnb_gas_rollups;
+
+DROP TABLE base_gas_rollups;
+
+DROP TABLE optimism_gas_rollups;
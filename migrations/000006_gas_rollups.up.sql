@@ -0,0 +1,95 @@
+CREATE TABLE IF NOT EXISTS ethereum_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS polygon_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS avalanche_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS bnb_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS base_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS optimism_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
@@ -0,0 +1 @@
+DROP TABLE missed_slots;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS missed_slots (
+    chain TEXT NOT NULL,
+    slot BIGINT NOT NULL,
+    slot_time TIMESTAMPTZ NOT NULL,
+    prev_block_number BIGINT NOT NULL,
+    prev_proposer TEXT,
+    next_block_number BIGINT NOT NULL,
+    next_proposer TEXT,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (chain, slot)
+);
+
+CREATE INDEX IF NOT EXISTS missed_slots_chain_time_idx ON missed_slots (chain, slot_time);
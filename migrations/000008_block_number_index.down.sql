@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS ethereum_block_metrics_block_number_idx;
+
+DROP INDEX IF EXISTS polygon_block_metrics_block_number_idx;
+
+DROP INDEX IF EXISTS avalanche_block_metrics_block_number_idx;
+
+DROP INDEX IF EXISTS bnb_block_metrics_block_number_idx;
+
+DROP INDEX IF EXISTS base_block_metrics_block_number_idx;
+
+DROP INDEX IF EXISTS optimism_block_metrics_block_number_idx;
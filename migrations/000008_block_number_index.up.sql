@@ -0,0 +1,13 @@
+-- Поиск по номеру блока (API, verify, backfill) без индекса сканировал все партиции:
+-- PK начинается с block_time, по которому идёт партиционирование.
+CREATE INDEX IF NOT EXISTS ethereum_block_metrics_block_number_idx ON ethereum_block_metrics (block_number);
+
+CREATE INDEX IF NOT EXISTS polygon_block_metrics_block_number_idx ON polygon_block_metrics (block_number);
+
+CREATE INDEX IF NOT EXISTS avalanche_block_metrics_block_number_idx ON avalanche_block_metrics (block_number);
+
+CREATE INDEX IF NOT EXISTS bnb_block_metrics_block_number_idx ON bnb_block_metrics (block_number);
+
+CREATE INDEX IF NOT EXISTS base_block_metrics_block_number_idx ON base_block_metrics (block_number);
+
+CREATE INDEX IF NOT EXISTS optimism_block_metrics_block_number_idx ON optimism_block_metrics (block_number);
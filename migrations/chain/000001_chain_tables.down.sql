@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS {{.Chain}}_gas_rollups;
+
+DROP TABLE IF EXISTS {{.Chain}}_mempool_snapshots;
+
+DROP TABLE IF EXISTS {{.Chain}}_block_metrics;
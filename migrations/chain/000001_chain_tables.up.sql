@@ -0,0 +1,59 @@
+-- Таблицы одной сети, {{.Chain}} подставляется для каждой настроенной сети.
+-- Схема совпадает с глобальными миграциями 000001-000006, для шести исходных сетей ничего не меняет.
+CREATE TABLE IF NOT EXISTS {{.Chain}}_block_metrics (
+    block_number BIGINT NOT NULL,
+    block_time TIMESTAMPTZ NOT NULL,
+    transactions_count INT,
+    block_size_bytes BIGINT,
+    gas_limit BIGINT,
+    gas_used BIGINT,
+    block_fullness DOUBLE PRECISION,
+    block_author TEXT,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_all_prices JSONB,
+    block_timestamp BIGINT NOT NULL,
+    l1_fee_total DOUBLE PRECISION,
+    l1_fee_avg DOUBLE PRECISION,
+    l1_gas_used BIGINT,
+    l1_base_fee_scalar BIGINT,
+    l1_blob_base_fee_scalar BIGINT,
+    deposit_tx_count INT,
+    unknown_tx_count INT NOT NULL DEFAULT 0,
+    base_fee DOUBLE PRECISION,
+    PRIMARY KEY (block_time, block_number)
+) PARTITION BY RANGE (block_time);
+
+CREATE TABLE IF NOT EXISTS {{.Chain}}_mempool_snapshots (
+    snapshot_time TIMESTAMPTZ NOT NULL PRIMARY KEY,
+    window_seconds BIGINT NOT NULL,
+    tx_count INT NOT NULL,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_stddev DOUBLE PRECISION,
+    gas_p25 DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p75 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    tip_p50 DOUBLE PRECISION,
+    tip_p95 DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS {{.Chain}}_gas_rollups (
+    granularity TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    block_count INT NOT NULL,
+    tx_count BIGINT NOT NULL,
+    gas_used_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    gas_min DOUBLE PRECISION,
+    gas_max DOUBLE PRECISION,
+    gas_avg DOUBLE PRECISION,
+    gas_p50 DOUBLE PRECISION,
+    gas_p95 DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (granularity, bucket_start)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS {{.Chain}}_transactions;
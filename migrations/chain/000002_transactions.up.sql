@@ -0,0 +1,22 @@
+-- Транзакции блоков, партиционированы по block_time как и {{.Chain}}_block_metrics.
+-- Цены в gwei, max_fee_per_gas и max_priority_fee_per_gas только у EIP-1559 транзакций.
+CREATE TABLE IF NOT EXISTS {{.Chain}}_transactions (
+    block_time TIMESTAMPTZ NOT NULL,
+    block_number BIGINT NOT NULL,
+    tx_index INT NOT NULL,
+    tx_hash TEXT NOT NULL,
+    from_address TEXT,
+    to_address TEXT,
+    tx_type SMALLINT NOT NULL,
+    gas_price DOUBLE PRECISION NOT NULL,
+    max_fee_per_gas DOUBLE PRECISION,
+    max_priority_fee_per_gas DOUBLE PRECISION,
+    tip DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (block_time, block_number, tx_index)
+) PARTITION BY RANGE (block_time);
+
+CREATE INDEX IF NOT EXISTS {{.Chain}}_transactions_hash_idx ON {{.Chain}}_transactions (tx_hash);
+
+CREATE INDEX IF NOT EXISTS {{.Chain}}_transactions_from_idx ON {{.Chain}}_transactions (from_address, block_time);
+
+CREATE INDEX IF NOT EXISTS {{.Chain}}_transactions_to_idx ON {{.Chain}}_transactions (to_address, block_time);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS {{.Chain}}_validators;
+
+ALTER TABLE {{.Chain}}_block_metrics DROP COLUMN IF EXISTS validator_counted;
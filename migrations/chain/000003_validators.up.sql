@@ -0,0 +1,41 @@
+-- Валидаторы сети (block_author), агрегаты обновляются майнером при сохранении блоков.
+-- avg_gas_price взвешена по транзакциям, avg_fullness и avg_tx_count - по блокам.
+CREATE TABLE IF NOT EXISTS {{.Chain}}_validators (
+    address TEXT NOT NULL PRIMARY KEY,
+    first_block BIGINT NOT NULL,
+    first_seen TIMESTAMPTZ NOT NULL,
+    last_block BIGINT NOT NULL,
+    last_seen TIMESTAMPTZ NOT NULL,
+    blocks_total BIGINT NOT NULL,
+    txs_total BIGINT NOT NULL,
+    avg_fullness DOUBLE PRECISION,
+    avg_tx_count DOUBLE PRECISION,
+    avg_gas_price DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS {{.Chain}}_validators_last_seen_idx ON {{.Chain}}_validators (last_seen);
+
+CREATE INDEX IF NOT EXISTS {{.Chain}}_validators_blocks_idx ON {{.Chain}}_validators (blocks_total DESC);
+
+-- Отметка, что блок уже добавлен к агрегатам {{.Chain}}_validators. Блок учитывается
+-- ровно один раз, в каком бы порядке ни приходили пачки (воркеры истории, backfill, repair).
+-- Уже сохранённые блоки учтены начальным заполнением ниже: для них DEFAULT true без
+-- перезаписи таблицы, новые строки получают false.
+ALTER TABLE {{.Chain}}_block_metrics ADD COLUMN IF NOT EXISTS validator_counted BOOLEAN NOT NULL DEFAULT true;
+
+ALTER TABLE {{.Chain}}_block_metrics ALTER COLUMN validator_counted SET DEFAULT false;
+
+-- Начальное заполнение из уже собранных блоков, дальше таблицу ведёт майнер
+INSERT INTO {{.Chain}}_validators (
+    address, first_block, first_seen, last_block, last_seen,
+    blocks_total, txs_total, avg_fullness, avg_tx_count, avg_gas_price
+)
+SELECT
+    block_author, MIN(block_number), MIN(block_time), MAX(block_number), MAX(block_time),
+    COUNT(*), COALESCE(SUM(transactions_count), 0), AVG(block_fullness), AVG(transactions_count),
+    SUM(gas_avg * transactions_count) / NULLIF(SUM(transactions_count), 0)
+FROM {{.Chain}}_block_metrics
+WHERE block_author IS NOT NULL AND block_author <> ''
+GROUP BY block_author
+ON CONFLICT (address) DO NOTHING;
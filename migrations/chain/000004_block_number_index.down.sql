@@ -0,0 +1 @@
+DROP INDEX IF EXISTS {{.Chain}}_block_metrics_block_number_idx;
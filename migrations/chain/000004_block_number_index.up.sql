@@ -0,0 +1,3 @@
+-- Поиск по номеру блока (API, verify, backfill) без индекса сканировал все партиции:
+-- PK начинается с block_time, по которому идёт партиционирование.
+CREATE INDEX IF NOT EXISTS {{.Chain}}_block_metrics_block_number_idx ON {{.Chain}}_block_metrics (block_number);
@@ -0,0 +1,12 @@
+// Package migrations встраивает SQL миграции в бинарник.
+// Корневые файлы применяются один раз на базу, chain/ - шаблоны с {{.Chain}},
+// применяются отдельно для каждой сети.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Global embed.FS
+
+//go:embed chain/*.sql
+var Chain embed.FS
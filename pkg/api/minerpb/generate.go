@@ -0,0 +1,3 @@
+package minerpb
+
+//go:generate protoc -I ../../../api/proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative miner.proto
@@ -0,0 +1,1331 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: miner.proto
+
+package minerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GasPrices struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float64                `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+	Avg           float64                `protobuf:"fixed64,3,opt,name=avg,proto3" json:"avg,omitempty"`
+	Stddev        float64                `protobuf:"fixed64,4,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	AllPrices     []float64              `protobuf:"fixed64,5,rep,packed,name=all_prices,json=allPrices,proto3" json:"all_prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GasPrices) Reset() {
+	*x = GasPrices{}
+	mi := &file_miner_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GasPrices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GasPrices) ProtoMessage() {}
+
+func (x *GasPrices) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GasPrices.ProtoReflect.Descriptor instead.
+func (*GasPrices) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GasPrices) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *GasPrices) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *GasPrices) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *GasPrices) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *GasPrices) GetAllPrices() []float64 {
+	if x != nil {
+		return x.AllPrices
+	}
+	return nil
+}
+
+type L1Fees struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Total             float64                `protobuf:"fixed64,1,opt,name=total,proto3" json:"total,omitempty"`
+	Avg               float64                `protobuf:"fixed64,2,opt,name=avg,proto3" json:"avg,omitempty"`
+	GasUsed           uint64                 `protobuf:"varint,3,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	BaseFeeScalar     uint64                 `protobuf:"varint,4,opt,name=base_fee_scalar,json=baseFeeScalar,proto3" json:"base_fee_scalar,omitempty"`
+	BlobBaseFeeScalar uint64                 `protobuf:"varint,5,opt,name=blob_base_fee_scalar,json=blobBaseFeeScalar,proto3" json:"blob_base_fee_scalar,omitempty"`
+	DepositCount      int64                  `protobuf:"varint,6,opt,name=deposit_count,json=depositCount,proto3" json:"deposit_count,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *L1Fees) Reset() {
+	*x = L1Fees{}
+	mi := &file_miner_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *L1Fees) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*L1Fees) ProtoMessage() {}
+
+func (x *L1Fees) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use L1Fees.ProtoReflect.Descriptor instead.
+func (*L1Fees) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *L1Fees) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *L1Fees) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *L1Fees) GetGasUsed() uint64 {
+	if x != nil {
+		return x.GasUsed
+	}
+	return 0
+}
+
+func (x *L1Fees) GetBaseFeeScalar() uint64 {
+	if x != nil {
+		return x.BaseFeeScalar
+	}
+	return 0
+}
+
+func (x *L1Fees) GetBlobBaseFeeScalar() uint64 {
+	if x != nil {
+		return x.BlobBaseFeeScalar
+	}
+	return 0
+}
+
+func (x *L1Fees) GetDepositCount() int64 {
+	if x != nil {
+		return x.DepositCount
+	}
+	return 0
+}
+
+type Block struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Chain             string                 `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	BlockNumber       uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	BlockTime         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"`
+	BlockTimestamp    uint64                 `protobuf:"varint,4,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	TransactionsCount int64                  `protobuf:"varint,5,opt,name=transactions_count,json=transactionsCount,proto3" json:"transactions_count,omitempty"`
+	UnknownTxCount    int64                  `protobuf:"varint,6,opt,name=unknown_tx_count,json=unknownTxCount,proto3" json:"unknown_tx_count,omitempty"`
+	BlockSizeBytes    uint64                 `protobuf:"varint,7,opt,name=block_size_bytes,json=blockSizeBytes,proto3" json:"block_size_bytes,omitempty"`
+	GasLimit          uint64                 `protobuf:"varint,8,opt,name=gas_limit,json=gasLimit,proto3" json:"gas_limit,omitempty"`
+	GasUsed           uint64                 `protobuf:"varint,9,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	BlockFullness     float64                `protobuf:"fixed64,10,opt,name=block_fullness,json=blockFullness,proto3" json:"block_fullness,omitempty"`
+	BaseFee           float64                `protobuf:"fixed64,11,opt,name=base_fee,json=baseFee,proto3" json:"base_fee,omitempty"`
+	Validator         string                 `protobuf:"bytes,12,opt,name=validator,proto3" json:"validator,omitempty"`
+	Gas               *GasPrices             `protobuf:"bytes,13,opt,name=gas,proto3" json:"gas,omitempty"`
+	L1Fees            *L1Fees                `protobuf:"bytes,14,opt,name=l1_fees,json=l1Fees,proto3" json:"l1_fees,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_miner_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Block) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *Block) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Block) GetBlockTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockTime
+	}
+	return nil
+}
+
+func (x *Block) GetBlockTimestamp() uint64 {
+	if x != nil {
+		return x.BlockTimestamp
+	}
+	return 0
+}
+
+func (x *Block) GetTransactionsCount() int64 {
+	if x != nil {
+		return x.TransactionsCount
+	}
+	return 0
+}
+
+func (x *Block) GetUnknownTxCount() int64 {
+	if x != nil {
+		return x.UnknownTxCount
+	}
+	return 0
+}
+
+func (x *Block) GetBlockSizeBytes() uint64 {
+	if x != nil {
+		return x.BlockSizeBytes
+	}
+	return 0
+}
+
+func (x *Block) GetGasLimit() uint64 {
+	if x != nil {
+		return x.GasLimit
+	}
+	return 0
+}
+
+func (x *Block) GetGasUsed() uint64 {
+	if x != nil {
+		return x.GasUsed
+	}
+	return 0
+}
+
+func (x *Block) GetBlockFullness() float64 {
+	if x != nil {
+		return x.BlockFullness
+	}
+	return 0
+}
+
+func (x *Block) GetBaseFee() float64 {
+	if x != nil {
+		return x.BaseFee
+	}
+	return 0
+}
+
+func (x *Block) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *Block) GetGas() *GasPrices {
+	if x != nil {
+		return x.Gas
+	}
+	return nil
+}
+
+func (x *Block) GetL1Fees() *L1Fees {
+	if x != nil {
+		return x.L1Fees
+	}
+	return nil
+}
+
+type Page struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Page) Reset() {
+	*x = Page{}
+	mi := &file_miner_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Page) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Page) ProtoMessage() {}
+
+func (x *Page) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Page.ProtoReflect.Descriptor instead.
+func (*Page) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Page) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Page) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type TimeRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeRange) Reset() {
+	*x = TimeRange{}
+	mi := &file_miner_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeRange) ProtoMessage() {}
+
+func (x *TimeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TimeRange) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TimeRange) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type NumberRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          uint64                 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint64                 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NumberRange) Reset() {
+	*x = NumberRange{}
+	mi := &file_miner_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NumberRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NumberRange) ProtoMessage() {}
+
+func (x *NumberRange) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NumberRange.ProtoReflect.Descriptor instead.
+func (*NumberRange) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NumberRange) GetFrom() uint64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NumberRange) GetTo() uint64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type GetBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chain         string                 `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	BlockNumber   uint64                 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBlockRequest) Reset() {
+	*x = GetBlockRequest{}
+	mi := &file_miner_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBlockRequest) ProtoMessage() {}
+
+func (x *GetBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBlockRequest.ProtoReflect.Descriptor instead.
+func (*GetBlockRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBlockRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *GetBlockRequest) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+type ListBlocksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Chain string                 `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	// Types that are valid to be assigned to Range:
+	//
+	//	*ListBlocksRequest_Numbers
+	//	*ListBlocksRequest_Time
+	Range         isListBlocksRequest_Range `protobuf_oneof:"range"`
+	Page          *Page                     `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlocksRequest) Reset() {
+	*x = ListBlocksRequest{}
+	mi := &file_miner_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlocksRequest) ProtoMessage() {}
+
+func (x *ListBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlocksRequest.ProtoReflect.Descriptor instead.
+func (*ListBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListBlocksRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *ListBlocksRequest) GetRange() isListBlocksRequest_Range {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *ListBlocksRequest) GetNumbers() *NumberRange {
+	if x != nil {
+		if x, ok := x.Range.(*ListBlocksRequest_Numbers); ok {
+			return x.Numbers
+		}
+	}
+	return nil
+}
+
+func (x *ListBlocksRequest) GetTime() *TimeRange {
+	if x != nil {
+		if x, ok := x.Range.(*ListBlocksRequest_Time); ok {
+			return x.Time
+		}
+	}
+	return nil
+}
+
+func (x *ListBlocksRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type isListBlocksRequest_Range interface {
+	isListBlocksRequest_Range()
+}
+
+type ListBlocksRequest_Numbers struct {
+	Numbers *NumberRange `protobuf:"bytes,2,opt,name=numbers,proto3,oneof"`
+}
+
+type ListBlocksRequest_Time struct {
+	Time *TimeRange `protobuf:"bytes,3,opt,name=time,proto3,oneof"`
+}
+
+func (*ListBlocksRequest_Numbers) isListBlocksRequest_Range() {}
+
+func (*ListBlocksRequest_Time) isListBlocksRequest_Range() {}
+
+type ListBlocksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blocks        []*Block               `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	NextOffset    int32                  `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlocksResponse) Reset() {
+	*x = ListBlocksResponse{}
+	mi := &file_miner_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlocksResponse) ProtoMessage() {}
+
+func (x *ListBlocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlocksResponse.ProtoReflect.Descriptor instead.
+func (*ListBlocksResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListBlocksResponse) GetBlocks() []*Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *ListBlocksResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+type GetGasStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chain         string                 `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Time          *TimeRange             `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGasStatsRequest) Reset() {
+	*x = GetGasStatsRequest{}
+	mi := &file_miner_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGasStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGasStatsRequest) ProtoMessage() {}
+
+func (x *GetGasStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGasStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetGasStatsRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetGasStatsRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *GetGasStatsRequest) GetTime() *TimeRange {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type GasStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	BlockCount    int64                  `protobuf:"varint,3,opt,name=block_count,json=blockCount,proto3" json:"block_count,omitempty"`
+	TxCount       int64                  `protobuf:"varint,4,opt,name=tx_count,json=txCount,proto3" json:"tx_count,omitempty"`
+	GasUsedTotal  int64                  `protobuf:"varint,5,opt,name=gas_used_total,json=gasUsedTotal,proto3" json:"gas_used_total,omitempty"`
+	AvgFullness   float64                `protobuf:"fixed64,6,opt,name=avg_fullness,json=avgFullness,proto3" json:"avg_fullness,omitempty"`
+	GasMin        float64                `protobuf:"fixed64,7,opt,name=gas_min,json=gasMin,proto3" json:"gas_min,omitempty"`
+	GasMax        float64                `protobuf:"fixed64,8,opt,name=gas_max,json=gasMax,proto3" json:"gas_max,omitempty"`
+	GasAvg        float64                `protobuf:"fixed64,9,opt,name=gas_avg,json=gasAvg,proto3" json:"gas_avg,omitempty"`
+	GasP50        float64                `protobuf:"fixed64,10,opt,name=gas_p50,json=gasP50,proto3" json:"gas_p50,omitempty"`
+	GasP95        float64                `protobuf:"fixed64,11,opt,name=gas_p95,json=gasP95,proto3" json:"gas_p95,omitempty"`
+	AvgBaseFee    float64                `protobuf:"fixed64,12,opt,name=avg_base_fee,json=avgBaseFee,proto3" json:"avg_base_fee,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GasStats) Reset() {
+	*x = GasStats{}
+	mi := &file_miner_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GasStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GasStats) ProtoMessage() {}
+
+func (x *GasStats) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GasStats.ProtoReflect.Descriptor instead.
+func (*GasStats) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GasStats) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GasStats) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GasStats) GetBlockCount() int64 {
+	if x != nil {
+		return x.BlockCount
+	}
+	return 0
+}
+
+func (x *GasStats) GetTxCount() int64 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasUsedTotal() int64 {
+	if x != nil {
+		return x.GasUsedTotal
+	}
+	return 0
+}
+
+func (x *GasStats) GetAvgFullness() float64 {
+	if x != nil {
+		return x.AvgFullness
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasMin() float64 {
+	if x != nil {
+		return x.GasMin
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasMax() float64 {
+	if x != nil {
+		return x.GasMax
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasAvg() float64 {
+	if x != nil {
+		return x.GasAvg
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasP50() float64 {
+	if x != nil {
+		return x.GasP50
+	}
+	return 0
+}
+
+func (x *GasStats) GetGasP95() float64 {
+	if x != nil {
+		return x.GasP95
+	}
+	return 0
+}
+
+func (x *GasStats) GetAvgBaseFee() float64 {
+	if x != nil {
+		return x.AvgBaseFee
+	}
+	return 0
+}
+
+type ListValidatorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chain         string                 `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Time          *TimeRange             `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Page          *Page                  `protobuf:"bytes,3,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListValidatorsRequest) Reset() {
+	*x = ListValidatorsRequest{}
+	mi := &file_miner_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListValidatorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListValidatorsRequest) ProtoMessage() {}
+
+func (x *ListValidatorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListValidatorsRequest.ProtoReflect.Descriptor instead.
+func (*ListValidatorsRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListValidatorsRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *ListValidatorsRequest) GetTime() *TimeRange {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ListValidatorsRequest) GetPage() *Page {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type Validator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Validator     string                 `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	Blocks        int64                  `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	FirstBlock    uint64                 `protobuf:"varint,3,opt,name=first_block,json=firstBlock,proto3" json:"first_block,omitempty"`
+	LastBlock     uint64                 `protobuf:"varint,4,opt,name=last_block,json=lastBlock,proto3" json:"last_block,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	AvgFullness   float64                `protobuf:"fixed64,6,opt,name=avg_fullness,json=avgFullness,proto3" json:"avg_fullness,omitempty"`
+	AvgTxCount    float64                `protobuf:"fixed64,7,opt,name=avg_tx_count,json=avgTxCount,proto3" json:"avg_tx_count,omitempty"`
+	AvgGasPrice   float64                `protobuf:"fixed64,8,opt,name=avg_gas_price,json=avgGasPrice,proto3" json:"avg_gas_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Validator) Reset() {
+	*x = Validator{}
+	mi := &file_miner_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Validator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Validator) ProtoMessage() {}
+
+func (x *Validator) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Validator.ProtoReflect.Descriptor instead.
+func (*Validator) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Validator) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *Validator) GetBlocks() int64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *Validator) GetFirstBlock() uint64 {
+	if x != nil {
+		return x.FirstBlock
+	}
+	return 0
+}
+
+func (x *Validator) GetLastBlock() uint64 {
+	if x != nil {
+		return x.LastBlock
+	}
+	return 0
+}
+
+func (x *Validator) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+func (x *Validator) GetAvgFullness() float64 {
+	if x != nil {
+		return x.AvgFullness
+	}
+	return 0
+}
+
+func (x *Validator) GetAvgTxCount() float64 {
+	if x != nil {
+		return x.AvgTxCount
+	}
+	return 0
+}
+
+func (x *Validator) GetAvgGasPrice() float64 {
+	if x != nil {
+		return x.AvgGasPrice
+	}
+	return 0
+}
+
+type ListValidatorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Validators    []*Validator           `protobuf:"bytes,1,rep,name=validators,proto3" json:"validators,omitempty"`
+	NextOffset    int32                  `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListValidatorsResponse) Reset() {
+	*x = ListValidatorsResponse{}
+	mi := &file_miner_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListValidatorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListValidatorsResponse) ProtoMessage() {}
+
+func (x *ListValidatorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListValidatorsResponse.ProtoReflect.Descriptor instead.
+func (*ListValidatorsResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListValidatorsResponse) GetValidators() []*Validator {
+	if x != nil {
+		return x.Validators
+	}
+	return nil
+}
+
+func (x *ListValidatorsResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+type StreamBlocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chains        []string               `protobuf:"bytes,1,rep,name=chains,proto3" json:"chains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBlocksRequest) Reset() {
+	*x = StreamBlocksRequest{}
+	mi := &file_miner_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBlocksRequest) ProtoMessage() {}
+
+func (x *StreamBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBlocksRequest.ProtoReflect.Descriptor instead.
+func (*StreamBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamBlocksRequest) GetChains() []string {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
+var File_miner_proto protoreflect.FileDescriptor
+
+const file_miner_proto_rawDesc = "" +
+	"\n" +
+	"\vminer.proto\x12\bminer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"x\n" +
+	"\tGasPrices\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x01R\x03max\x12\x10\n" +
+	"\x03avg\x18\x03 \x01(\x01R\x03avg\x12\x16\n" +
+	"\x06stddev\x18\x04 \x01(\x01R\x06stddev\x12\x1d\n" +
+	"\n" +
+	"all_prices\x18\x05 \x03(\x01R\tallPrices\"\xc9\x01\n" +
+	"\x06L1Fees\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x01R\x05total\x12\x10\n" +
+	"\x03avg\x18\x02 \x01(\x01R\x03avg\x12\x19\n" +
+	"\bgas_used\x18\x03 \x01(\x04R\agasUsed\x12&\n" +
+	"\x0fbase_fee_scalar\x18\x04 \x01(\x04R\rbaseFeeScalar\x12/\n" +
+	"\x14blob_base_fee_scalar\x18\x05 \x01(\x04R\x11blobBaseFeeScalar\x12#\n" +
+	"\rdeposit_count\x18\x06 \x01(\x03R\fdepositCount\"\x91\x04\n" +
+	"\x05Block\x12\x14\n" +
+	"\x05chain\x18\x01 \x01(\tR\x05chain\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\x129\n" +
+	"\n" +
+	"block_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tblockTime\x12'\n" +
+	"\x0fblock_timestamp\x18\x04 \x01(\x04R\x0eblockTimestamp\x12-\n" +
+	"\x12transactions_count\x18\x05 \x01(\x03R\x11transactionsCount\x12(\n" +
+	"\x10unknown_tx_count\x18\x06 \x01(\x03R\x0eunknownTxCount\x12(\n" +
+	"\x10block_size_bytes\x18\a \x01(\x04R\x0eblockSizeBytes\x12\x1b\n" +
+	"\tgas_limit\x18\b \x01(\x04R\bgasLimit\x12\x19\n" +
+	"\bgas_used\x18\t \x01(\x04R\agasUsed\x12%\n" +
+	"\x0eblock_fullness\x18\n" +
+	" \x01(\x01R\rblockFullness\x12\x19\n" +
+	"\bbase_fee\x18\v \x01(\x01R\abaseFee\x12\x1c\n" +
+	"\tvalidator\x18\f \x01(\tR\tvalidator\x12%\n" +
+	"\x03gas\x18\r \x01(\v2\x13.miner.v1.GasPricesR\x03gas\x12)\n" +
+	"\al1_fees\x18\x0e \x01(\v2\x10.miner.v1.L1FeesR\x06l1Fees\"4\n" +
+	"\x04Page\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"g\n" +
+	"\tTimeRange\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"1\n" +
+	"\vNumberRange\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x04R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x04R\x02to\"J\n" +
+	"\x0fGetBlockRequest\x12\x14\n" +
+	"\x05chain\x18\x01 \x01(\tR\x05chain\x12!\n" +
+	"\fblock_number\x18\x02 \x01(\x04R\vblockNumber\"\xb4\x01\n" +
+	"\x11ListBlocksRequest\x12\x14\n" +
+	"\x05chain\x18\x01 \x01(\tR\x05chain\x121\n" +
+	"\anumbers\x18\x02 \x01(\v2\x15.miner.v1.NumberRangeH\x00R\anumbers\x12)\n" +
+	"\x04time\x18\x03 \x01(\v2\x13.miner.v1.TimeRangeH\x00R\x04time\x12\"\n" +
+	"\x04page\x18\x04 \x01(\v2\x0e.miner.v1.PageR\x04pageB\a\n" +
+	"\x05range\"^\n" +
+	"\x12ListBlocksResponse\x12'\n" +
+	"\x06blocks\x18\x01 \x03(\v2\x0f.miner.v1.BlockR\x06blocks\x12\x1f\n" +
+	"\vnext_offset\x18\x02 \x01(\x05R\n" +
+	"nextOffset\"S\n" +
+	"\x12GetGasStatsRequest\x12\x14\n" +
+	"\x05chain\x18\x01 \x01(\tR\x05chain\x12'\n" +
+	"\x04time\x18\x02 \x01(\v2\x13.miner.v1.TimeRangeR\x04time\"\x8a\x03\n" +
+	"\bGasStats\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1f\n" +
+	"\vblock_count\x18\x03 \x01(\x03R\n" +
+	"blockCount\x12\x19\n" +
+	"\btx_count\x18\x04 \x01(\x03R\atxCount\x12$\n" +
+	"\x0egas_used_total\x18\x05 \x01(\x03R\fgasUsedTotal\x12!\n" +
+	"\favg_fullness\x18\x06 \x01(\x01R\vavgFullness\x12\x17\n" +
+	"\agas_min\x18\a \x01(\x01R\x06gasMin\x12\x17\n" +
+	"\agas_max\x18\b \x01(\x01R\x06gasMax\x12\x17\n" +
+	"\agas_avg\x18\t \x01(\x01R\x06gasAvg\x12\x17\n" +
+	"\agas_p50\x18\n" +
+	" \x01(\x01R\x06gasP50\x12\x17\n" +
+	"\agas_p95\x18\v \x01(\x01R\x06gasP95\x12 \n" +
+	"\favg_base_fee\x18\f \x01(\x01R\n" +
+	"avgBaseFee\"z\n" +
+	"\x15ListValidatorsRequest\x12\x14\n" +
+	"\x05chain\x18\x01 \x01(\tR\x05chain\x12'\n" +
+	"\x04time\x18\x02 \x01(\v2\x13.miner.v1.TimeRangeR\x04time\x12\"\n" +
+	"\x04page\x18\x03 \x01(\v2\x0e.miner.v1.PageR\x04page\"\xa3\x02\n" +
+	"\tValidator\x12\x1c\n" +
+	"\tvalidator\x18\x01 \x01(\tR\tvalidator\x12\x16\n" +
+	"\x06blocks\x18\x02 \x01(\x03R\x06blocks\x12\x1f\n" +
+	"\vfirst_block\x18\x03 \x01(\x04R\n" +
+	"firstBlock\x12\x1d\n" +
+	"\n" +
+	"last_block\x18\x04 \x01(\x04R\tlastBlock\x127\n" +
+	"\tlast_seen\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\x12!\n" +
+	"\favg_fullness\x18\x06 \x01(\x01R\vavgFullness\x12 \n" +
+	"\favg_tx_count\x18\a \x01(\x01R\n" +
+	"avgTxCount\x12\"\n" +
+	"\ravg_gas_price\x18\b \x01(\x01R\vavgGasPrice\"n\n" +
+	"\x16ListValidatorsResponse\x123\n" +
+	"\n" +
+	"validators\x18\x01 \x03(\v2\x13.miner.v1.ValidatorR\n" +
+	"validators\x12\x1f\n" +
+	"\vnext_offset\x18\x02 \x01(\x05R\n" +
+	"nextOffset\"-\n" +
+	"\x13StreamBlocksRequest\x12\x16\n" +
+	"\x06chains\x18\x01 \x03(\tR\x06chains2\xe7\x02\n" +
+	"\fMinerService\x126\n" +
+	"\bGetBlock\x12\x19.miner.v1.GetBlockRequest\x1a\x0f.miner.v1.Block\x12G\n" +
+	"\n" +
+	"ListBlocks\x12\x1b.miner.v1.ListBlocksRequest\x1a\x1c.miner.v1.ListBlocksResponse\x12?\n" +
+	"\vGetGasStats\x12\x1c.miner.v1.GetGasStatsRequest\x1a\x12.miner.v1.GasStats\x12S\n" +
+	"\x0eListValidators\x12\x1f.miner.v1.ListValidatorsRequest\x1a .miner.v1.ListValidatorsResponse\x12@\n" +
+	"\fStreamBlocks\x12\x1d.miner.v1.StreamBlocksRequest\x1a\x0f.miner.v1.Block0\x01B/Z-blocks_gas_validators/pkg/api/minerpb;minerpbb\x06proto3"
+
+var (
+	file_miner_proto_rawDescOnce sync.Once
+	file_miner_proto_rawDescData []byte
+)
+
+func file_miner_proto_rawDescGZIP() []byte {
+	file_miner_proto_rawDescOnce.Do(func() {
+		file_miner_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_miner_proto_rawDesc), len(file_miner_proto_rawDesc)))
+	})
+	return file_miner_proto_rawDescData
+}
+
+var file_miner_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_miner_proto_goTypes = []any{
+	(*GasPrices)(nil),              // 0: miner.v1.GasPrices
+	(*L1Fees)(nil),                 // 1: miner.v1.L1Fees
+	(*Block)(nil),                  // 2: miner.v1.Block
+	(*Page)(nil),                   // 3: miner.v1.Page
+	(*TimeRange)(nil),              // 4: miner.v1.TimeRange
+	(*NumberRange)(nil),            // 5: miner.v1.NumberRange
+	(*GetBlockRequest)(nil),        // 6: miner.v1.GetBlockRequest
+	(*ListBlocksRequest)(nil),      // 7: miner.v1.ListBlocksRequest
+	(*ListBlocksResponse)(nil),     // 8: miner.v1.ListBlocksResponse
+	(*GetGasStatsRequest)(nil),     // 9: miner.v1.GetGasStatsRequest
+	(*GasStats)(nil),               // 10: miner.v1.GasStats
+	(*ListValidatorsRequest)(nil),  // 11: miner.v1.ListValidatorsRequest
+	(*Validator)(nil),              // 12: miner.v1.Validator
+	(*ListValidatorsResponse)(nil), // 13: miner.v1.ListValidatorsResponse
+	(*StreamBlocksRequest)(nil),    // 14: miner.v1.StreamBlocksRequest
+	(*timestamppb.Timestamp)(nil),  // 15: google.protobuf.Timestamp
+}
+var file_miner_proto_depIdxs = []int32{
+	15, // 0: miner.v1.Block.block_time:type_name -> google.protobuf.Timestamp
+	0,  // 1: miner.v1.Block.gas:type_name -> miner.v1.GasPrices
+	1,  // 2: miner.v1.Block.l1_fees:type_name -> miner.v1.L1Fees
+	15, // 3: miner.v1.TimeRange.from:type_name -> google.protobuf.Timestamp
+	15, // 4: miner.v1.TimeRange.to:type_name -> google.protobuf.Timestamp
+	5,  // 5: miner.v1.ListBlocksRequest.numbers:type_name -> miner.v1.NumberRange
+	4,  // 6: miner.v1.ListBlocksRequest.time:type_name -> miner.v1.TimeRange
+	3,  // 7: miner.v1.ListBlocksRequest.page:type_name -> miner.v1.Page
+	2,  // 8: miner.v1.ListBlocksResponse.blocks:type_name -> miner.v1.Block
+	4,  // 9: miner.v1.GetGasStatsRequest.time:type_name -> miner.v1.TimeRange
+	15, // 10: miner.v1.GasStats.from:type_name -> google.protobuf.Timestamp
+	15, // 11: miner.v1.GasStats.to:type_name -> google.protobuf.Timestamp
+	4,  // 12: miner.v1.ListValidatorsRequest.time:type_name -> miner.v1.TimeRange
+	3,  // 13: miner.v1.ListValidatorsRequest.page:type_name -> miner.v1.Page
+	15, // 14: miner.v1.Validator.last_seen:type_name -> google.protobuf.Timestamp
+	12, // 15: miner.v1.ListValidatorsResponse.validators:type_name -> miner.v1.Validator
+	6,  // 16: miner.v1.MinerService.GetBlock:input_type -> miner.v1.GetBlockRequest
+	7,  // 17: miner.v1.MinerService.ListBlocks:input_type -> miner.v1.ListBlocksRequest
+	9,  // 18: miner.v1.MinerService.GetGasStats:input_type -> miner.v1.GetGasStatsRequest
+	11, // 19: miner.v1.MinerService.ListValidators:input_type -> miner.v1.ListValidatorsRequest
+	14, // 20: miner.v1.MinerService.StreamBlocks:input_type -> miner.v1.StreamBlocksRequest
+	2,  // 21: miner.v1.MinerService.GetBlock:output_type -> miner.v1.Block
+	8,  // 22: miner.v1.MinerService.ListBlocks:output_type -> miner.v1.ListBlocksResponse
+	10, // 23: miner.v1.MinerService.GetGasStats:output_type -> miner.v1.GasStats
+	13, // 24: miner.v1.MinerService.ListValidators:output_type -> miner.v1.ListValidatorsResponse
+	2,  // 25: miner.v1.MinerService.StreamBlocks:output_type -> miner.v1.Block
+	21, // [21:26] is the sub-list for method output_type
+	16, // [16:21] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_miner_proto_init() }
+func file_miner_proto_init() {
+	if File_miner_proto != nil {
+		return
+	}
+	file_miner_proto_msgTypes[7].OneofWrappers = []any{
+		(*ListBlocksRequest_Numbers)(nil),
+		(*ListBlocksRequest_Time)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_miner_proto_rawDesc), len(file_miner_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_miner_proto_goTypes,
+		DependencyIndexes: file_miner_proto_depIdxs,
+		MessageInfos:      file_miner_proto_msgTypes,
+	}.Build()
+	File_miner_proto = out.File
+	file_miner_proto_goTypes = nil
+	file_miner_proto_depIdxs = nil
+}
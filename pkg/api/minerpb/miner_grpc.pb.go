@@ -0,0 +1,277 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: miner.proto
+
+package minerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MinerService_GetBlock_FullMethodName       = "/miner.v1.MinerService/GetBlock"
+	MinerService_ListBlocks_FullMethodName     = "/miner.v1.MinerService/ListBlocks"
+	MinerService_GetGasStats_FullMethodName    = "/miner.v1.MinerService/GetGasStats"
+	MinerService_ListValidators_FullMethodName = "/miner.v1.MinerService/ListValidators"
+	MinerService_StreamBlocks_FullMethodName   = "/miner.v1.MinerService/StreamBlocks"
+)
+
+// MinerServiceClient is the client API for MinerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MinerServiceClient interface {
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	ListBlocks(ctx context.Context, in *ListBlocksRequest, opts ...grpc.CallOption) (*ListBlocksResponse, error)
+	GetGasStats(ctx context.Context, in *GetGasStatsRequest, opts ...grpc.CallOption) (*GasStats, error)
+	ListValidators(ctx context.Context, in *ListValidatorsRequest, opts ...grpc.CallOption) (*ListValidatorsResponse, error)
+	StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Block], error)
+}
+
+type minerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMinerServiceClient(cc grpc.ClientConnInterface) MinerServiceClient {
+	return &minerServiceClient{cc}
+}
+
+func (c *minerServiceClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Block)
+	err := c.cc.Invoke(ctx, MinerService_GetBlock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) ListBlocks(ctx context.Context, in *ListBlocksRequest, opts ...grpc.CallOption) (*ListBlocksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlocksResponse)
+	err := c.cc.Invoke(ctx, MinerService_ListBlocks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) GetGasStats(ctx context.Context, in *GetGasStatsRequest, opts ...grpc.CallOption) (*GasStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GasStats)
+	err := c.cc.Invoke(ctx, MinerService_GetGasStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) ListValidators(ctx context.Context, in *ListValidatorsRequest, opts ...grpc.CallOption) (*ListValidatorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListValidatorsResponse)
+	err := c.cc.Invoke(ctx, MinerService_ListValidators_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Block], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MinerService_ServiceDesc.Streams[0], MinerService_StreamBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamBlocksRequest, Block]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MinerService_StreamBlocksClient = grpc.ServerStreamingClient[Block]
+
+// MinerServiceServer is the server API for MinerService service.
+// All implementations must embed UnimplementedMinerServiceServer
+// for forward compatibility.
+type MinerServiceServer interface {
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	ListBlocks(context.Context, *ListBlocksRequest) (*ListBlocksResponse, error)
+	GetGasStats(context.Context, *GetGasStatsRequest) (*GasStats, error)
+	ListValidators(context.Context, *ListValidatorsRequest) (*ListValidatorsResponse, error)
+	StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[Block]) error
+	mustEmbedUnimplementedMinerServiceServer()
+}
+
+// UnimplementedMinerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMinerServiceServer struct{}
+
+func (UnimplementedMinerServiceServer) GetBlock(context.Context, *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedMinerServiceServer) ListBlocks(context.Context, *ListBlocksRequest) (*ListBlocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlocks not implemented")
+}
+func (UnimplementedMinerServiceServer) GetGasStats(context.Context, *GetGasStatsRequest) (*GasStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGasStats not implemented")
+}
+func (UnimplementedMinerServiceServer) ListValidators(context.Context, *ListValidatorsRequest) (*ListValidatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListValidators not implemented")
+}
+func (UnimplementedMinerServiceServer) StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[Block]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
+func (UnimplementedMinerServiceServer) mustEmbedUnimplementedMinerServiceServer() {}
+func (UnimplementedMinerServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeMinerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MinerServiceServer will
+// result in compilation errors.
+type UnsafeMinerServiceServer interface {
+	mustEmbedUnimplementedMinerServiceServer()
+}
+
+func RegisterMinerServiceServer(s grpc.ServiceRegistrar, srv MinerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMinerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MinerService_ServiceDesc, srv)
+}
+
+func _MinerService_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinerService_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_ListBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).ListBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinerService_ListBlocks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).ListBlocks(ctx, req.(*ListBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_GetGasStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGasStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).GetGasStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinerService_GetGasStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).GetGasStats(ctx, req.(*GetGasStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_ListValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListValidatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).ListValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MinerService_ListValidators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).ListValidators(ctx, req.(*ListValidatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MinerServiceServer).StreamBlocks(m, &grpc.GenericServerStream[StreamBlocksRequest, Block]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MinerService_StreamBlocksServer = grpc.ServerStreamingServer[Block]
+
+// MinerService_ServiceDesc is the grpc.ServiceDesc for MinerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MinerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "miner.v1.MinerService",
+	HandlerType: (*MinerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBlock",
+			Handler:    _MinerService_GetBlock_Handler,
+		},
+		{
+			MethodName: "ListBlocks",
+			Handler:    _MinerService_ListBlocks_Handler,
+		},
+		{
+			MethodName: "GetGasStats",
+			Handler:    _MinerService_GetGasStats_Handler,
+		},
+		{
+			MethodName: "ListValidators",
+			Handler:    _MinerService_ListValidators_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _MinerService_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "miner.proto",
+}
@@ -6,6 +6,23 @@ type ChainInfo struct {
 	URL         string
 	BlockTime   float64
 	EtherscanId string
+	// OPStack отмечает L2 на OP-stack, где в receipts есть поля L1 data fee
+	OPStack bool
+	// SlotGenesis - unix время нулевого слота для сетей с фиксированными слотами,
+	// 0 если слотов нет
+	SlotGenesis int64
+	// SlotsFromBlock - первый блок, производимый по слотам (для ethereum - The Merge)
+	SlotsFromBlock uint64
+}
+
+// SlotAt возвращает номер слота для timestamp блока
+func (c ChainInfo) SlotAt(timestamp uint64) uint64 {
+	return uint64((int64(timestamp) - c.SlotGenesis) / int64(c.BlockTime))
+}
+
+// SlotTime возвращает unix время начала слота
+func (c ChainInfo) SlotTime(slot uint64) int64 {
+	return c.SlotGenesis + int64(slot)*int64(c.BlockTime)
 }
 
 var AlchemyChains = map[string]ChainInfo{
@@ -15,6 +32,10 @@ var AlchemyChains = map[string]ChainInfo{
 		URL:         "://eth-mainnet.g.alchemy.com/v2/",
 		BlockTime:   12.0,
 		EtherscanId: "1",
+		// Genesis beacon chain, 2020-12-01 12:00:23 UTC
+		SlotGenesis: 1606824023,
+		// До The Merge блоки шли по PoW без слотов
+		SlotsFromBlock: 15537394,
 	},
 	"polygon": {
 		Name:        "polygon",
@@ -43,6 +64,7 @@ var AlchemyChains = map[string]ChainInfo{
 		URL:         "://opt-mainnet.g.alchemy.com/v2/",
 		BlockTime:   2.0,
 		EtherscanId: "10",
+		OPStack:     true,
 	},
 	"base": {
 		Name:        "base",
@@ -50,5 +72,6 @@ var AlchemyChains = map[string]ChainInfo{
 		URL:         "://base-mainnet.g.alchemy.com/v2/",
 		BlockTime:   2.0,
 		EtherscanId: "2",
+		OPStack:     true,
 	},
 }
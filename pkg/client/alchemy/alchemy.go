@@ -25,7 +25,8 @@ func NewAlchemyClient(cfg configs.AlchemyConfig, logger *logging.Logger) (*Clien
 	apiKey := os.Getenv(cfg.NameApiKey)
 
 	var fullURL string
-	if cfg.Mode == "last" {
+	// Подписки (новые блоки, мемпул) работают только по websocket
+	if cfg.Mode == "last" || cfg.Mode == "mempool" {
 		fullURL = fmt.Sprintf("wss%s%s", chains.AlchemyChains[cfg.NetworkName].URL, apiKey)
 	} else {
 		fullURL = fmt.Sprintf("https%s%s", chains.AlchemyChains[cfg.NetworkName].URL, apiKey)
@@ -0,0 +1,27 @@
+package utilits
+
+import "math"
+
+// Percentile возвращает p-й перцентиль (0..100) уже отсортированного среза
+// с линейной интерполяцией между соседними значениями.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}